@@ -0,0 +1,143 @@
+package fides
+
+import "errors"
+
+// BatteryFIT returns the FIT of a battery or coin cell (class BT)
+func BatteryFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(BatteryEval(comp, mission))
+}
+
+// BatteryEval returns the detailed FIT of a battery: the random failures of
+// the cells (N cells in series), and the end of life by calendar ageing,
+// charge/discharge cycles and, for primary cells, the capacity used.
+//
+// Ops is the number of charge/discharge cycles per hour (or per phase in the
+// loads file), Cycles the rated cycle life and Life the calendar life (h) at
+// Tref (default 20 ºC, the reference of Arrhenius25). For primary cells, Value
+// is the capacity (Ah) and I the mean load current. Calendar ageing has its
+// own activation energy (about doubling every 10 ºC) and goes on also when the
+// equipment is off.
+func BatteryEval(comp *Component, mission *Mission) (*Result, error) {
+
+	chem := batteryChemistry(comp.Tags)
+	fit, ea, lth, ltc, lm, lrh, calendar, eacal, cycles := lbase_battery(chem)
+
+	if comp.N > 1 {
+		fit *= float64(comp.N)
+	}
+	if comp.Life > 0 {
+		calendar = comp.Life
+	}
+	if comp.Cycles > 0 {
+		cycles = comp.Cycles
+	}
+
+	tref := 20.0
+	if comp.Tref != 0 {
+		tref = comp.Tref
+	}
+
+	r := newResult(fit)
+
+	// Fraction of the life used by the mission
+	used := 0.0
+	coldCharge := false
+
+	for _, ph := range mission.Phases {
+
+		// Working conditions in this phase
+		c := comp.InPhase(ph)
+		tamb := mission.Tamb(comp, ph)
+
+		if tamb > comp.Tmax {
+			return nil, errors.New("Using component above its Tmax")
+		}
+
+		// Proportion of time in this phase
+		w := ph.Duration / mission.Ttotal
+
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+
+		// Batteries are always powered
+		pi.Thermal = w * lth * Arrhenius25(ea, tamb)
+		pi.TCycling = w * ltc * PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax)
+		pi.Humidity = w * lrh * PiRH(0.8, ph.RH, tamb)
+		pi.Mechanical = w * lm * PiMech(ph.Grms)
+
+		// Stress factors and sensibility
+		ifactor, err := PiInduced(comp, ph)
+		if err != nil {
+			return nil, err
+		}
+		pi.Induced = ifactor
+
+		// Calendar ageing
+		used += ph.Duration * ArrheniusK(eacal, tref+273, tamb+273) / calendar
+
+		// Charge/discharge cycles
+		if cycles > 0 && c.Ops > 0 {
+			used += ph.Duration * c.Ops / cycles
+			if tamb < 0 && (chem == "liion" || chem == "lifepo4") {
+				coldCharge = true
+			}
+		}
+
+		// Capacity of primary cells
+		if cycles == 0 && comp.Value > 0 && c.I > 0 {
+			used += ph.Duration * c.I / comp.Value
+		}
+
+		r.add(pi)
+	}
+
+	if coldCharge {
+		r.warn("Charge cycles below 0 ºC (lithium plating)")
+	}
+
+	if used > 0 {
+		r.Life = mission.Ttotal / used
+		if r.Life < mission.Ttotal {
+			r.warn("End of life (%.0f h) before the end of the mission (%.0f h)", r.Life, mission.Ttotal)
+		}
+	}
+
+	return r, nil
+}
+
+// batteryChemistry returns limno2 (lithium coin cells, default), liion,
+// lifepo4 or nimh
+func batteryChemistry(tags []string) string {
+
+	for _, tag := range tags {
+		switch tag {
+		case "liion", "li-ion", "lipo":
+			return "liion"
+		case "lifepo4", "lfp":
+			return "lifepo4"
+		case "nimh":
+			return "nimh"
+		}
+	}
+	return "limno2"
+}
+
+// Returns l0, ea, lth, ltc, lmech, lrh, the calendar life (h) at 20 ºC, the
+// activation energy of calendar ageing and the rated cycle life (0 for
+// primary cells)
+func lbase_battery(chem string) (float64, float64, float64, float64, float64, float64, float64, float64, float64) {
+
+	const year = 8760.0
+
+	switch chem {
+	case "liion":
+		return 10, 0.5, 0.5, 0.2, 0.2, 0.1, 10 * year, 0.55, 500
+	case "lifepo4":
+		return 8, 0.45, 0.5, 0.2, 0.2, 0.1, 15 * year, 0.5, 2000
+	case "nimh":
+		return 5, 0.4, 0.5, 0.2, 0.2, 0.1, 5 * year, 0.55, 500
+	}
+
+	// Coin cells: the holder or the welded tabs, and the corrosion of the
+	// contacts
+	return 1, 0.6, 0.3, 0.3, 0.2, 0.2, 10 * year, 0.55, 0
+}
@@ -0,0 +1,86 @@
+package fides
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBatteryChemistry(t *testing.T) {
+
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{[]string{"coin"}, "limno2"},
+		{[]string{"li-ion"}, "liion"},
+		{[]string{"lipo"}, "liion"},
+		{[]string{"lfp"}, "lifepo4"},
+		{[]string{"nimh"}, "nimh"},
+	}
+
+	for _, tt := range tests {
+		if got := batteryChemistry(tt.tags); got != tt.want {
+			t.Errorf("batteryChemistry(%v) = %s, want %s", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestBatteryEval(t *testing.T) {
+
+	const year = 8760.0
+
+	// Expected life, from the fraction of the life used per hour
+	life := func(used float64) float64 { return 1 / used }
+
+	tests := []struct {
+		name string
+		tamb float64
+		comp *Component
+		life float64
+		warn string
+	}{
+		{"coin cell at tref", 20, &Component{Class: "BT", Tags: []string{"coin"}, Tmax: 60},
+			10 * year, ""},
+		{"coin cell, given tref and life", 40, &Component{Class: "BT", Tags: []string{"coin"}, Tmax: 60, Tref: 40, Life: 5 * year},
+			5 * year, ""},
+		{"coin cell, hot", 40, &Component{Class: "BT", Tags: []string{"coin"}, Tmax: 60},
+			10 * year / ArrheniusK(0.55, 293, 313), ""},
+		{"coin cell capacity", 20, &Component{Class: "BT", Tags: []string{"coin"}, Tmax: 60, Value: 0.22, I: 10e-6},
+			life(1/(10*year) + 10e-6/0.22), ""},
+		{"liion cycles", 20, &Component{Class: "BT", Tags: []string{"liion"}, Tmax: 60, Ops: 1.0 / 24, Cycles: 1000},
+			life(1/(10*year) + 1.0/24/1000), ""},
+		{"liion short life", 20, &Component{Class: "BT", Tags: []string{"liion"}, Tmax: 60, Ops: 1},
+			life(1/(10*year) + 1.0/500), "End of life"},
+		{"lfp cold charge", -10, &Component{Class: "BT", Tags: []string{"lfp"}, Tmax: 60, Ops: 0.001},
+			life(ArrheniusK(0.5, 293, 263)/(15*year) + 0.001/2000), "below 0 ºC"},
+		{"nimh cold", -10, &Component{Class: "BT", Tags: []string{"nimh"}, Tmax: 60, Ops: 0.001},
+			life(ArrheniusK(0.55, 293, 263)/(5*year) + 0.001/500), ""},
+	}
+
+	for _, tt := range tests {
+
+		r, err := BatteryEval(tt.comp, testMission(tt.tamb))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !near(r.Life, tt.life) {
+			t.Errorf("%s: life %g, want %g", tt.name, r.Life, tt.life)
+		}
+		warnings := strings.Join(r.Warnings, "; ")
+		if tt.warn == "" && warnings != "" || !strings.Contains(warnings, tt.warn) {
+			t.Errorf("%s: warnings %q, want %q", tt.name, warnings, tt.warn)
+		}
+	}
+
+	// Random failures of the cells in series
+	r1, err1 := BatteryEval(&Component{Class: "BT", Tags: []string{"liion"}, Tmax: 60}, testMission(25))
+	r3, err3 := BatteryEval(&Component{Class: "BT", Tags: []string{"liion"}, Tmax: 60, N: 3}, testMission(25))
+	if err1 != nil || err3 != nil || !near(r3.FIT, 3*r1.FIT) {
+		t.Errorf("FIT of 3 cells %g, want 3 x %g", r3.FIT, r1.FIT)
+	}
+
+	if _, err := BatteryEval(&Component{Class: "BT", Tmax: 60}, testMission(70)); err == nil {
+		t.Error("no error above Tmax")
+	}
+}
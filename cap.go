@@ -2,32 +2,33 @@ package fides
 
 import (
 	"errors"
+	"fmt"
 	"math"
+	"strings"
 )
 
 func CapacitorFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(CapacitorEval(comp, mission))
+}
+
+func CapacitorEval(comp *Component, mission *Mission) (*Result, error) {
 
 	// Vmax and V are needed for capacitors
 
 	if comp.Vmax == 0 || math.IsNaN(comp.Vmax) {
-		return math.NaN(), errors.New("Vmax not set")
-	}
-
-	if comp.V == 0 || math.IsNaN(comp.V) {
-		return math.NaN(), errors.New("working V not set")
-	}
-
-	if comp.V > comp.Vmax {
-		return math.NaN(), errors.New("working V higher than limit Vmax")
+		return nil, errors.New("Vmax not set")
 	}
 
-	// Determine basic type (alu, tant, cer)
+	// Determine basic type (alu, tant, cer, film, super)
 	ctype := capType(comp.Tags)
 	if ctype == "" {
-		return math.NaN(), errors.New("unknown capacitor type " + ctype)
+		return nil, errors.New("unknown capacitor type " + ctype)
 	}
 
-	var fit, factor, ea, sref, lth, ltc, lm float64
+	var fit, ea, sref, lth, ltc, lm float64
+
+	// Flex-crack sensitivity of MLCCs (0 for other types)
+	kflex := 0.0
 
 	if ctype == "cer" {
 
@@ -35,12 +36,25 @@ func CapacitorFIT(comp *Component, mission *Mission) (float64, error) {
 		type1 := contains(comp.Tags, "np0") || contains(comp.Tags, "c0g") || contains(comp.Tags, "type1")
 		topend := contains(comp.Tags, "topend")
 		fit, ea, sref, lth, ltc, lm = lbase_capCer(flex, type1, topend, comp.Value, comp.Vmax)
+		sref = cerVoltageRef(comp.Tags, sref)
+
+		size := mlccSize(comp.Package)
+		kflex = mlccFlex(size, flex)
 
 	} else if ctype == "alu" {
 
 		dry := contains(comp.Tags, "dry") || contains(comp.Tags, "solid")
 		fit, ea, sref, lth, ltc, lm = lbase_capAlu(dry)
 
+	} else if ctype == "film" {
+
+		safety := contains(comp.Tags, "x1") || contains(comp.Tags, "x2") || contains(comp.Tags, "y1") || contains(comp.Tags, "y2")
+		fit, ea, sref, lth, ltc, lm = lbase_capFilm(filmDielectric(comp.Tags), safety)
+
+	} else if ctype == "super" {
+
+		fit, ea, sref, lth, ltc, lm = lbase_capSuper()
+
 	} else { // tant
 
 		smd := contains(comp.Tags, "smd") || IsSmd(comp)
@@ -48,32 +62,81 @@ func CapacitorFIT(comp *Component, mission *Mission) (float64, error) {
 
 	}
 
+	r := newResult(fit)
+
+	// Fraction of the rated endurance used by the mission (alu, super)
+	used := 0.0
+
 	for _, ph := range mission.Phases {
 
+		// Local ambient temperature
+		tamb := mission.Tamb(comp, ph)
+
 		// General rule
-		if ph.Tamb > comp.Tmax {
-			return math.NaN(), errors.New("Using component above its Tmax")
+		if tamb > comp.Tmax {
+			return nil, errors.New("Using component above its Tmax")
 		}
 
-		pi := lth*PiThermal_cap(ea, ph.Tamb, sref, comp.V/comp.Vmax, ph.On) +
-			ltc*PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax) +
-			lm*PiMech(ph.Grms)
+		// Working conditions in this phase
+		c := comp.InPhase(ph)
+		on := mission.IsOn(comp, ph)
+
+		if on && (c.V == 0 || math.IsNaN(c.V)) {
+			return nil, errors.New("working V not set")
+		}
+		if c.V > comp.Vmax {
+			return nil, errors.New("working V higher than limit Vmax")
+		}
+
+		// Core (hotspot) temperature of electrolytic capacitors
+		tcap := tamb
+		if on && ctype == "alu" {
+			tcap += aluRise(comp)
+			if tcap > comp.Tmax {
+				s := fmt.Sprintf("Core temperature (%f ºC) exceeds its Tmax (%f ºC), ripple=%f A", tcap, comp.Tmax, comp.Ripple)
+				return nil, errors.New(s)
+			}
+		}
+
+		if on {
+			switch ctype {
+			case "alu":
+				used += ph.Duration / aluLife(comp, tcap)
+			case "super":
+				used += ph.Duration / superCapLife(comp, c.V, tamb)
+			}
+		}
 
 		// Proportion of time in this phase
-		pi *= ph.Duration / mission.Ttotal
+		w := ph.Duration / mission.Ttotal
+
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+		pi.Thermal = w * lth * PiThermal_cap(ea, tcap, sref, c.V/comp.Vmax, on)
+		pi.TCycling = w * ltc * PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax)
+		pi.Mechanical = w * (lm*PiMech(ph.Grms) + mlccFlexStress*kflex*ph.Bending)
 
 		// Stress factors and sensibility
 		ifactor, err := PiInduced(comp, ph)
 		if err != nil {
-			return math.NaN(), err
+			return nil, err
 		}
-		pi *= ifactor
+		pi.Induced = ifactor
+
+		r.add(pi)
+	}
 
-		factor += pi
+	if ctype == "cer" && mlccEdgeRisk(comp) {
+		r.warn("Large MLCC (%s) without flexible terminations near a board edge or connector: risk of flex cracks", mlccSize(comp.Package))
+	}
 
+	if used > 0 {
+		r.Life = mission.Ttotal / used
+		if r.Life < mission.Ttotal {
+			r.warn("End of life by wear-out (%.0f h) before the end of the mission (%.0f h)", r.Life, mission.Ttotal)
+		}
 	}
 
-	return fit * factor * PiPM() * PiProcess(), nil
+	return r, nil
 }
 
 // https://en.wikipedia.org/wiki/Ceramic_capacitor
@@ -84,6 +147,108 @@ func CapacitorFIT(comp *Component, mission *Mission) (float64, error) {
 
 var cerCapTags = []string{"x5r", "x5s", "x6r", "x6s", "x7r", "x7s", "x8r", "x8s", "np0", "c0g", "y5v"}
 
+// Film dielectrics and safety classes (film is polyester if not given)
+var filmCapTags = []string{"film", "pp", "pet", "pps", "mkp", "mkt", "x1", "x2", "y1", "y2"}
+
+// mlccFlexStress is the flex-crack term of an 0805 MLCC per level of board
+// bending (Phase.Bending: low 1, moderate 2, high 4), on the scale of the
+// other lambdas of the capacitor. At high bending an 0805 adds 0.4, of the
+// order of the thermal cycling term of MLCCs (0.14 to 0.51) and well above
+// their vibration term (0.01 to 0.05), as flex cracks are the main cause of
+// MLCC field failures on boards that are bent.
+const mlccFlexStress = 0.1
+
+// MLCC case sizes (EIA, inches)
+var mlccSizes = []string{"0201", "0402", "0603", "0805", "1206", "1210", "1808", "1812", "1825", "2220", "2225"}
+
+// Metric case sizes and their EIA equivalent. The metric 0603 (EIA 0201) is
+// left out, as it cannot be told apart from the EIA 0603.
+var mlccMetric = map[string]string{
+	"1005": "0402", "1608": "0603", "2012": "0805", "3216": "1206", "3225": "1210",
+	"4520": "1808", "4532": "1812", "4564": "1825", "5750": "2220", "5763": "2225", "5764": "2225",
+}
+
+// mlccSize returns the EIA case size contained in the package name (0805,
+// C0805, 0805_2012, or the metric 2012), or "" if none.
+func mlccSize(pkg string) string {
+
+	for _, size := range mlccSizes {
+		if strings.Contains(pkg, size) {
+			return size
+		}
+	}
+	for metric, size := range mlccMetric {
+		if strings.Contains(pkg, metric) {
+			return size
+		}
+	}
+	return ""
+}
+
+// mlccFlex returns the sensitivity of an MLCC to flex cracks, relative to an
+// 0805. Cracks grow with the length of the part and, for the same length,
+// with its width (1210 vs 1206). Flexible terminations reduce it by 4.
+// Unknown sizes are taken as 0805.
+func mlccFlex(size string, flex bool) float64 {
+
+	k := 1.0
+	switch size {
+	case "0201":
+		k = 0.2
+	case "0402":
+		k = 0.4
+	case "0603":
+		k = 0.6
+	case "1206":
+		k = 1.5
+	case "1210":
+		k = 2.5
+	case "1808":
+		k = 3
+	case "1812":
+		k = 4
+	case "1825", "2220", "2225":
+		k = 6
+	}
+
+	if flex {
+		k /= 4
+	}
+	return k
+}
+
+// cerVoltageRef returns the reference voltage ratio of ceramic capacitors for
+// the dielectric: DC bias stresses high-K dielectrics (X5R to X6S, Y5V) more
+// than X7R (and X7S, X8R, X8S), and C0G much less.
+func cerVoltageRef(tags []string, sref float64) float64 {
+
+	for _, tag := range tags {
+		switch tag {
+		case "np0", "c0g", "type1":
+			return 0.5
+		case "x5r", "x5s", "x6r", "x6s":
+			return 0.25
+		case "y5v":
+			return 0.2
+		}
+	}
+	return sref
+}
+
+// mlccEdgeRisk returns true for class 2 MLCCs of 1206 or larger, without
+// flexible terminations, placed near a board edge or a connector (tags edge,
+// connector).
+func mlccEdgeRisk(comp *Component) bool {
+
+	if contains(comp.Tags, "flex") || contains(comp.Tags, "np0") || contains(comp.Tags, "c0g") || contains(comp.Tags, "type1") {
+		return false
+	}
+	if !contains(comp.Tags, "edge") && !contains(comp.Tags, "connector") {
+		return false
+	}
+	return mlccFlex(mlccSize(comp.Package), false) >= 1.5
+}
+
 // Can be improved to return tolerance and temperature limits
 func capType(tags []string) string {
 
@@ -101,6 +266,16 @@ func capType(tags []string) string {
 		}
 	}
 
+	if contains(tags, "supercap") || contains(tags, "edlc") {
+		return "super"
+	}
+
+	for _, tag := range filmCapTags {
+		if contains(tags, tag) {
+			return "film"
+		}
+	}
+
 	return ""
 }
 
@@ -186,3 +361,95 @@ func lbase_capTant(tags []string, smd bool) (float64, float64, float64, float64,
 	return 1.09, 0.15, 0.4, 0.86, 0.12, 0.02
 
 }
+
+// filmDielectric returns pp (polypropylene), pps (polyphenylene sulfide) or
+// pet (polyester, default)
+func filmDielectric(tags []string) string {
+
+	if contains(tags, "pp") || contains(tags, "mkp") {
+		return "pp"
+	}
+	if contains(tags, "pps") {
+		return "pps"
+	}
+	return "pet"
+}
+
+// Film capacitors are self-healing: the voltage stress is referred to a higher
+// ratio than ceramics. Safety (X/Y) capacitors see the mains transients and
+// have a higher base rate.
+func lbase_capFilm(dielectric string, safety bool) (float64, float64, float64, float64, float64, float64) {
+
+	k := 1.0
+	if safety {
+		k = 1.5
+	}
+
+	switch dielectric {
+	case "pp":
+		return 0.07 * k, 0.3, 0.7, 0.8, 0.15, 0.05
+	case "pps":
+		return 0.09 * k, 0.25, 0.6, 0.8, 0.15, 0.05
+	}
+	return 0.1 * k, 0.35, 0.6, 0.8, 0.15, 0.05
+}
+
+// Electric double-layer capacitors: electrolyte decomposition is strongly
+// accelerated by temperature and voltage.
+func lbase_capSuper() (float64, float64, float64, float64, float64, float64) {
+	return 2.0, 0.6, 0.8, 0.9, 0.08, 0.02
+}
+
+// Rated endurance of supercapacitors (h) at Tmax and Vmax, to a capacitance
+// loss of 30%
+const superCapEndurance = 1000.0
+
+// superCapLife returns the life (h) of a supercapacitor at voltage v and
+// temperature t, limited by capacitance fade. The life doubles every 10 ºC
+// below Tmax and every 0.1 V below the rated voltage of a 2.7 V cell.
+func superCapLife(comp *Component, v, t float64) float64 {
+
+	l0 := superCapEndurance
+	if comp.Life > 0 {
+		l0 = comp.Life
+	}
+	return l0 * math.Pow(2, (comp.Tmax-t)/10) * math.Pow(2, (1-v/comp.Vmax)*27)
+}
+
+// Rated endurance of aluminium electrolytic capacitors (h) at Tmax, and the
+// maximum life (15 years) set by the ageing of the seal
+const (
+	aluEndurance = 2000.0
+	aluMaxLife   = 15 * 8760.0
+)
+
+// aluRise returns the temperature rise of the core of an electrolytic
+// capacitor due to the ripple current: Ripple² * ESR * Rth. Rth is Rtha if
+// given, else a typical value for the size.
+func aluRise(comp *Component) float64 {
+
+	if comp.Ripple <= 0 || comp.Esr <= 0 {
+		return 0
+	}
+
+	rth := comp.Rtha
+	if rth <= 0 {
+		if IsSmd(comp) {
+			rth = 40
+		} else {
+			rth = 25
+		}
+	}
+	return comp.Ripple * comp.Ripple * comp.Esr * rth
+}
+
+// aluLife returns the life (h) of an aluminium electrolytic capacitor with a
+// core temperature t: the rated endurance doubles every 10 ºC below Tmax.
+func aluLife(comp *Component, t float64) float64 {
+
+	l0 := aluEndurance
+	if comp.Life > 0 {
+		l0 = comp.Life
+	}
+	return math.Min(l0*math.Pow(2, (comp.Tmax-t)/10), aluMaxLife)
+}
@@ -0,0 +1,287 @@
+package fides
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCerVoltageRef(t *testing.T) {
+
+	tests := []struct {
+		tags []string
+		want float64
+	}{
+		{[]string{"cer"}, 0.3},
+		{[]string{"x7r"}, 0.3},
+		{[]string{"x7s"}, 0.3},
+		{[]string{"x8r"}, 0.3},
+		{[]string{"c0g"}, 0.5},
+		{[]string{"np0"}, 0.5},
+		{[]string{"x5r"}, 0.25},
+		{[]string{"x5s"}, 0.25},
+		{[]string{"x6r"}, 0.25},
+		{[]string{"x6s"}, 0.25},
+		{[]string{"y5v"}, 0.2},
+	}
+
+	for _, tt := range tests {
+		if got := cerVoltageRef(tt.tags, 0.3); got != tt.want {
+			t.Errorf("cerVoltageRef(%v) = %g, want %g", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestMlccSize(t *testing.T) {
+
+	tests := []struct {
+		pkg, want string
+	}{
+		{"0805", "0805"},
+		{"C0805", "0805"},
+		{"0805_2012", "0805"},
+		{"2012", "0805"},
+		{"C3216", "1206"},
+		{"1210", "1210"},
+		{"SMD", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := mlccSize(tt.pkg); got != tt.want {
+			t.Errorf("mlccSize(%q) = %q, want %q", tt.pkg, got, tt.want)
+		}
+	}
+}
+
+func TestMlccFlex(t *testing.T) {
+
+	tests := []struct {
+		size string
+		flex bool
+		want float64
+	}{
+		{"0805", false, 1},
+		{"", false, 1},
+		{"0402", false, 0.4},
+		{"1206", false, 1.5},
+		{"1206", true, 0.375},
+	}
+
+	for _, tt := range tests {
+		if got := mlccFlex(tt.size, tt.flex); !near(got, tt.want) {
+			t.Errorf("mlccFlex(%q, %v) = %g, want %g", tt.size, tt.flex, got, tt.want)
+		}
+	}
+
+	// Larger and wider cases are more sensitive
+	sizes := []string{"0201", "0402", "0603", "0805", "1206", "1210", "1812", "2220"}
+	for i := 1; i < len(sizes); i++ {
+		if mlccFlex(sizes[i], false) <= mlccFlex(sizes[i-1], false) {
+			t.Errorf("mlccFlex(%s) <= mlccFlex(%s)", sizes[i], sizes[i-1])
+		}
+	}
+}
+
+func TestCapType(t *testing.T) {
+
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{[]string{"x7r"}, "cer"},
+		{[]string{"tant"}, "tant"},
+		{[]string{"alu"}, "alu"},
+		{[]string{"film"}, "film"},
+		{[]string{"mkp"}, "film"},
+		{[]string{"x2"}, "film"},
+		{[]string{"supercap"}, "super"},
+		{[]string{"edlc"}, "super"},
+		{[]string{"smd"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := capType(tt.tags); got != tt.want {
+			t.Errorf("capType(%v) = %q, want %q", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestFilmDielectric(t *testing.T) {
+
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{[]string{"film"}, "pet"},
+		{[]string{"mkt"}, "pet"},
+		{[]string{"pp"}, "pp"},
+		{[]string{"mkp", "x2"}, "pp"},
+		{[]string{"pps"}, "pps"},
+	}
+
+	for _, tt := range tests {
+		if got := filmDielectric(tt.tags); got != tt.want {
+			t.Errorf("filmDielectric(%v) = %q, want %q", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestSuperCapLife(t *testing.T) {
+
+	tests := []struct {
+		name string
+		life float64
+		v, t float64
+		want float64
+	}{
+		{"rated", 0, 2.7, 65, 1000},
+		{"10 ºC below", 0, 2.7, 55, 2000},
+		{"0.1 V below", 0, 2.6, 65, 2000},
+		{"given life", 1500, 2.7, 45, 6000},
+	}
+
+	for _, tt := range tests {
+		c := &Component{Tmax: 65, Vmax: 2.7, Life: tt.life}
+		if got := superCapLife(c, tt.v, tt.t); !near(got, tt.want) {
+			t.Errorf("%s: superCapLife = %g, want %g", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCapacitorEvalFilm(t *testing.T) {
+
+	m := testMission(40)
+
+	fit := func(tags ...string) float64 {
+		c := &Component{Class: "C", Tags: tags, Tmax: 105, Vmax: 275, V: 230}
+		r, err := CapacitorEval(c, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r.FIT
+	}
+
+	if fit("pp", "x2") <= fit("pp") {
+		t.Error("safety capacitors do not have a higher FIT")
+	}
+	if fit("pp") >= fit("pet") {
+		t.Error("polypropylene has not a lower FIT than polyester")
+	}
+}
+
+func TestCapacitorEvalSuper(t *testing.T) {
+
+	m := testMission(40)
+
+	tests := []struct {
+		name string
+		v    float64
+		warn bool
+	}{
+		{"derated", 2.3, false},
+		{"at rated voltage", 2.7, true},
+	}
+
+	for _, tt := range tests {
+		c := &Component{Class: "C", Tags: []string{"supercap"}, Tmax: 65, Vmax: 2.7, V: tt.v}
+		r, err := CapacitorEval(c, m)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		want := 8760 / (1000 * math.Pow(2, 2.5) * math.Pow(2, (1-tt.v/2.7)*27))
+		if !near(m.Ttotal/r.Life, want) {
+			t.Errorf("%s: life %g, want %g", tt.name, r.Life, 8760/want)
+		}
+		if (len(r.Warnings) > 0) != tt.warn {
+			t.Errorf("%s: warnings %v", tt.name, r.Warnings)
+		}
+	}
+}
+
+func TestAluRise(t *testing.T) {
+
+	tests := []struct {
+		name string
+		comp *Component
+		want float64
+	}{
+		{"no ripple", &Component{Esr: 0.1}, 0},
+		{"no esr", &Component{Ripple: 1}, 0},
+		{"smd", &Component{Ripple: 1, Esr: 0.1}, 4},
+		{"tht", &Component{Ripple: 1, Esr: 0.1, Package: "DIP"}, 2.5},
+		{"rtha", &Component{Ripple: 2, Esr: 0.1, Rtha: 20}, 8},
+	}
+
+	for _, tt := range tests {
+		if got := aluRise(tt.comp); !near(got, tt.want) {
+			t.Errorf("%s: aluRise = %g, want %g", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAluLife(t *testing.T) {
+
+	tests := []struct {
+		name string
+		life float64
+		t    float64
+		want float64
+	}{
+		{"rated", 0, 105, 2000},
+		{"20 ºC below", 0, 85, 8000},
+		{"given life", 5000, 95, 10000},
+		{"seal", 0, 25, 15 * 8760},
+	}
+
+	for _, tt := range tests {
+		c := &Component{Tmax: 105, Life: tt.life}
+		if got := aluLife(c, tt.t); !near(got, tt.want) {
+			t.Errorf("%s: aluLife = %g, want %g", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCapacitorEvalAlu(t *testing.T) {
+
+	m := testMission(65)
+
+	tests := []struct {
+		name      string
+		tmax      float64
+		endurance float64
+		ripple    float64
+		life      float64
+		warn      bool
+		err       bool
+	}{
+		{"no ripple", 105, 0, 0, 2000 * 16, false, false},
+		{"ripple", 105, 0, 1, 2000 * 16 / math.Pow(2, 0.4), false, false},
+		{"short life", 85, 1000, 0, 4000, true, false},
+		{"core above tmax", 105, 0, 4, 0, false, true},
+	}
+
+	for _, tt := range tests {
+
+		c := &Component{Class: "C", Tags: []string{"alu"}, Tmax: tt.tmax, Life: tt.endurance, Vmax: 25, V: 12,
+			Ripple: tt.ripple, Esr: 0.1}
+
+		r, err := CapacitorEval(c, m)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%s: no error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !near(r.Life, tt.life) {
+			t.Errorf("%s: life %g, want %g", tt.name, r.Life, tt.life)
+		}
+		if (len(r.Warnings) > 0) != tt.warn {
+			t.Errorf("%s: warnings %v", tt.name, r.Warnings)
+		}
+	}
+}
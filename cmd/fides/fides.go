@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -12,13 +13,34 @@ import (
 func main() {
 
 	var err error
-	var md bool
+	var md, detail, jsn bool
+	var loads, domains, zones, rollup, sortBy, design string
 
 	flag.BoolVar(&md, "md", false, "output markdown format")
+	flag.BoolVar(&jsn, "json", false, "output JSON format (BOM, mission and results)")
+	flag.BoolVar(&detail, "detail", false, "show the contribution of each mechanism and the dominant phase")
+	flag.StringVar(&loads, "loads", "", "CSV file with the working conditions per component and phase")
+	flag.StringVar(&domains, "domains", "", "CSV file with the power domains of each block")
+	flag.StringVar(&zones, "zones", "", "CSV file with the thermal zones")
+	flag.StringVar(&rollup, "rollup", "", "comma separated list of FIT summaries: block, class, tag")
+	flag.StringVar(&sortBy, "sort", "", "sort the components by this field (name, fit, class, block, package, type, v, p, ...)")
+	flag.StringVar(&design, "design", "", "JSON file with a design and its subassemblies and modules")
 	flag.Parse()
 
+	if flag.Arg(0) == "validate" {
+		validate(flag.Args()[1:])
+		return
+	}
+
+	if design != "" {
+		evalDesign(design, flag.Arg(0), md, jsn)
+		return
+	}
+
 	if flag.NArg() < 2 {
-		fmt.Println("Usage: fides [options] <bom.csv> [db.csv] [work.csv] <mission.csv>")
+		fmt.Println("Usage: fides [options] <bom.csv|bom.json> [db.csv] [work.csv] <mission.csv|mission.json>")
+		fmt.Println("       fides [-md|-json] -design design.json [mission.csv|mission.json]")
+		fmt.Println("       fides validate <bom.csv> [db.csv] [work.csv]")
 		os.Exit(1)
 	}
 
@@ -29,58 +51,285 @@ func main() {
 	for n = 0; n < flag.NArg()-1; n++ {
 		files = append(files, flag.Arg(n))
 	}
-	err = bom.FromCsvs(files)
-	if err!=nil {
-		fmt.Printf(err.Error())
+	if len(files) == 1 && strings.HasSuffix(files[0], ".json") {
+		err = bom.FromJson(files[0])
+	} else {
+		err = bom.FromCsvs(files)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
 		os.Exit(-1)
 	}
 
+	// Working conditions per phase
+	if loads != "" {
+		err = bom.LoadsFromCsv(loads)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(-1)
+		}
+	}
+
 	// The mission
 	mission := &fides.Mission{}
-	mission.FromCsv(flag.Arg(n))
+	if strings.HasSuffix(flag.Arg(n), ".json") {
+		err = mission.FromJson(flag.Arg(n))
+	} else {
+		err = mission.FromCsv(flag.Arg(n))
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(-1)
+	}
+
+	// Power domains
+	if domains != "" {
+		err = mission.DomainsFromCsv(domains)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(-1)
+		}
+	}
+
+	// Thermal zones
+	if zones != "" {
+		err = mission.ZonesFromCsv(zones)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(-1)
+		}
+	}
 
 	// The result
-	
-	var fit float64
+
+	rep := fides.NewReport(bom, mission)
+	fit := rep.FIT
+
+	evals := make(map[*fides.Component]*fides.Evaluation)
+	for i, c := range bom.Components {
+		evals[c] = rep.Results[i]
+	}
+
+	if sortBy != "" {
+		err = bom.Sort(sortBy)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(-1)
+		}
+		for i, c := range bom.Components {
+			rep.Results[i] = evals[c]
+		}
+	}
+
+	if jsn {
+		s, err := rep.ToJson()
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(-1)
+		}
+		fmt.Println(s)
+		return
+	}
 
 	if md {
-        fmt.Println("# FIDES 2022 analysis\n\n## FIT values\n")
-		fmt.Println("| Name | FIT | Class | Tags | Package | Conditions |")
-		fmt.Println("|---|---|---|---|---|---|")
+		fmt.Print("# FIDES 2022 analysis\n\n## FIT values\n\n")
+		if detail {
+			fmt.Println("| Name | FIT | Thermal | TCycling | Mechanical | Humidity | Chemical | Wearout | Phase | Class | Tags | Package | Conditions |")
+			fmt.Println("|---|---|---|---|---|---|---|---|---|---|---|---|---|")
+		} else {
+			fmt.Println("| Name | FIT | Class | Tags | Package | Conditions |")
+			fmt.Println("|---|---|---|---|---|---|")
+		}
 	} else {
-		fmt.Println("name, fit, class, tags, package, npins, power")
+		if detail {
+			fmt.Println("name, fit, thermal, tcycling, mechanical, humidity, chemical, wearout, phase, class, tags, package, npins, power")
+		} else {
+			fmt.Println("name, fit, class, tags, package, npins, power")
+		}
 	}
 	for _, c := range bom.Components {
 
-		c.FIT, err = fides.FIT(c, mission)
-
-		sfit := ""
+		// FIT (or error) and, in detail, the mechanisms and dominant phase
+		var fields []string
 
-		if err != nil {
-			sfit = err.Error()
+		if e := evals[c]; e.Error != "" {
+			msg := e.Error
+			if md {
+				msg = strings.ReplaceAll(msg, "|", "\\|")
+			}
+			fields = append(fields, msg)
+			if detail {
+				fields = append(fields, make([]string, 7)...)
+			}
 		} else {
-			sfit = fmt.Sprintf("%.4f", c.FIT)
-			fit += c.FIT
+			r := evals[c].Result
+			fields = append(fields, fmt.Sprintf("%.4f", c.FIT))
+
+			if detail {
+				for _, f := range []float64{r.Thermal(), r.TCycling(), r.Mechanical(), r.Humidity(), r.Chemical(), r.Wearout()} {
+					fields = append(fields, fmt.Sprintf("%.4f", f))
+				}
+				fields = append(fields, r.Dominant())
+			}
 		}
 
-		tags := ""
-		for _, tag := range c.Tags {
-			tags += " " + tag
+		sep := ", "
+		if md {
+			sep = " | "
 		}
+		sfit := strings.Join(fields, sep)
+
+		tags := strings.Join(c.Tags, " ")
 
-		cond := fmt.Sprintf("V=%f V, P=%f W",c.V,c.P)
+		cond := fmt.Sprintf("V=%f V, P=%f W", c.V, c.P)
 
 		if md {
-			fmt.Printf("| %s | %s | %s | %s | %s | %s |\n", strings.ToUpper(c.Name), sfit, c.Class, tags[1:], c.Package, cond)
+			fmt.Printf("| %s | %s | %s | %s | %s | %s |\n", strings.ToUpper(c.Name), sfit, c.Class, tags, c.Package, cond)
 		} else {
-			fmt.Printf("%s, %s, %s, %s, %s, %d, %f\n", strings.ToUpper(c.Name), sfit, c.Class, tags[1:], c.Package, c.Np, c.P)
-	    }
+			fmt.Printf("%s, %s, %s, %s, %s, %d, %f\n", strings.ToUpper(c.Name), sfit, c.Class, tags, c.Package, c.Np, c.P)
+		}
 	}
 
 	fmt.Printf("\n FIT TOTAL = %f\n\n", fit)
 
+	// Warnings (wear-out before the end of the mission)
+	warnings := false
+	for _, c := range bom.Components {
+		r := evals[c].Result
+		if r == nil {
+			continue
+		}
+		for _, w := range r.Warnings {
+			if md {
+				if !warnings {
+					fmt.Printf("## Warnings\n\n")
+				}
+				fmt.Printf("- %s: %s\n", strings.ToUpper(c.Name), w)
+			} else {
+				fmt.Printf("WARNING %s: %s\n", strings.ToUpper(c.Name), w)
+			}
+			warnings = true
+		}
+	}
+	if warnings {
+		fmt.Println()
+	}
+
+	if rollup != "" {
+		for _, by := range strings.Split(rollup, ",") {
+			by = strings.TrimSpace(by)
+			groups, err := bom.Rollup(by, 5)
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(-1)
+			}
+			if md {
+				fmt.Printf("## FIT per %s\n\n", by)
+				fmt.Print(fides.GroupsToMD(by, groups))
+			} else {
+				fmt.Print(fides.GroupsToCsv(by, groups))
+			}
+			fmt.Println()
+		}
+	}
+
 	if md {
 		fmt.Printf("## Mission profile\n\n")
 		fmt.Print(mission.ToMD())
+
+		if len(mission.Zones) > 0 {
+			fmt.Printf("\n## Thermal zones\n\n")
+			fmt.Print(mission.ZonesToMD())
+		}
+	}
+}
+
+// validate checks the BOM files and prints the problems found
+func validate(files []string) {
+
+	if len(files) == 0 {
+		fmt.Println("Usage: fides validate <bom.csv> [db.csv] [work.csv]")
+		os.Exit(1)
+	}
+
+	dd, err := fides.ValidateCsvs(files)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(-1)
+	}
+
+	for _, d := range dd {
+		fmt.Println(d.String())
+	}
+
+	if len(dd) > 0 {
+		os.Exit(1)
+	}
+}
+
+// evalDesign prints the FIT of a design and its subassemblies and modules.
+// The mission file, if given, overrides the mission of the design.
+func evalDesign(file, missionFile string, md, jsn bool) {
+
+	d, err := fides.LoadDesign(file)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(-1)
+	}
+
+	var mission *fides.Mission
+	if missionFile != "" {
+		mission = &fides.Mission{}
+		if strings.HasSuffix(missionFile, ".json") {
+			err = mission.FromJson(missionFile)
+		} else {
+			err = mission.FromCsv(missionFile)
+		}
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(-1)
+		}
+	}
+
+	r, err := d.Evaluate(mission)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(-1)
+	}
+
+	if jsn {
+		b, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(-1)
+		}
+		fmt.Println(string(b))
+		return
+	}
+
+	groups := r.Rollup(5)
+	if md {
+		fmt.Print("# FIDES 2022 analysis\n\n## FIT per design\n\n")
+		fmt.Print(fides.GroupsToMD("design", groups))
+	} else {
+		fmt.Print(fides.GroupsToCsv("design", groups))
+	}
+
+	fmt.Printf("\n FIT TOTAL = %f\n\n", r.FIT)
+
+	// Components left out of the FIT
+	ee := r.Errors()
+	for i, e := range ee {
+		if md {
+			if i == 0 {
+				fmt.Printf("## Errors (not included in the FIT)\n\n")
+			}
+			fmt.Printf("- %s\n", e)
+		} else {
+			fmt.Printf("ERROR %s\n", e)
+		}
+	}
+	if len(ee) > 0 {
+		fmt.Println()
 	}
 }
@@ -1,7 +1,9 @@
 package fides
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	e "github.com/rveen/electronics"
 	"github.com/rveen/golib/csv"
 	"strconv"
@@ -19,11 +21,13 @@ type Component struct {
 	Class string
 	Tags  []string
 	Block string // For classifying per block or function
+	Zone  string // Thermal zone (optional, else the zone of the block)
 
 	Package string
-	N       int // Number of devices
-	Np      int // TODO Number of pins
-	Rtha    float64
+	N       int     // Number of devices
+	Np      int     // TODO Number of pins
+	Rtha    float64 // Thermal resistance to ambient (ºC/W), overrides the package value
+	Rca     float64 // Case (heatsink) to ambient thermal resistance (ºC/W), optional
 
 	Vp, V, P, I, T                float64 // Working conditions (T is the delta over ambient)
 	Vpmax, Vmax, Pmax, Imax, Tmax float64 // Device limits
@@ -31,15 +35,223 @@ type Component struct {
 	// Temperature coefficient. Set to NaN for undefined
 	TC float64
 
+	// Wear-out of switching parts (relays, switches)
+	Ops    float64 // Operations (switching cycles, actuations) per hour
+	Duty   float64 // Fraction of time that a relay coil is energized
+	Cycles float64 // Rated (electrical) life in operations
+
+	// Wear-out of capacitors (aluminium electrolytic, supercapacitors)
+	Ripple float64 // Ripple current (A rms)
+	Esr    float64 // Equivalent series resistance (Ω) at the ripple frequency
+	Life   float64 // Rated endurance (h) at Tmax (at Tref for fans and batteries)
+	Tref   float64 // Reference temperature of the rated life (ºC), fans and batteries
+	Speed  float64 // Speed of a fan as a fraction of its rated speed
+
+	// Deep sub-micron ICs: technology node (nm), core voltage (V) and
+	// activity factor (0..1)
+	Node, Vcore, Activity float64
+
+	// RF parts: RF power and its rating (W), frequency (Hz)
+	Prf, Prfmax, Freq float64
+
+	// Dies of a hybrid or multi-chip module, as "category[:count][@rating] ..."
+	Dies string
+
+	// Pulses (current sense resistors)
+	Ip float64 // Peak current of a pulse (A)
+	Tp float64 // Length of a pulse (s)
+
+	// Protection devices (varistors, GDTs, TVS)
+	Surge      float64 // Energy of each surge (J)
+	Surges     float64 // Surges per hour
+	Emax       float64 // Rated surge energy (J)
+	Vwithstand float64 // Maximum transient voltage of the protected circuit (V)
+
+	// Working conditions per phase (key is the phase name). Optional.
+	Loads map[string]*Load
+
 	FIT float64
 }
 
+// Load holds the working conditions of a component in one phase. Fields that
+// are NaN are taken from the component.
+type Load struct {
+	V, P, I, T float64
+	Ops        float64 // Number of operations in the phase
+	Duty       float64
+	Speed      float64
+	Surge      float64 // Energy of each surge (J)
+	Surges     float64 // Number of surges in the phase
+	Prf        float64
+}
+
+func NewLoad() *Load {
+	return &Load{V: math.NaN(), P: math.NaN(), I: math.NaN(), T: math.NaN(), Ops: math.NaN(), Duty: math.NaN(),
+		Speed: math.NaN(), Surge: math.NaN(), Surges: math.NaN(), Prf: math.NaN()}
+}
+
+// InPhase returns a copy of the component with the working conditions of the
+// given phase (the values of the load defined for that phase, if any). The
+// models can change the copy (e.g. set P from V) without affecting other
+// phases.
+func (c *Component) InPhase(ph *Phase) *Component {
+
+	cp := *c
+
+	ld := c.Loads[strings.ToLower(ph.Name)]
+	if ld == nil {
+		return &cp
+	}
+
+	if !math.IsNaN(ld.V) {
+		cp.V = ld.V
+	}
+	if !math.IsNaN(ld.P) {
+		cp.P = ld.P
+	}
+	if !math.IsNaN(ld.I) {
+		cp.I = ld.I
+	}
+	if !math.IsNaN(ld.T) {
+		cp.T = ld.T
+	}
+	if !math.IsNaN(ld.Ops) && ph.Duration > 0 {
+		cp.Ops = ld.Ops / ph.Duration
+	}
+	if !math.IsNaN(ld.Duty) {
+		cp.Duty = ld.Duty
+	}
+	if !math.IsNaN(ld.Speed) {
+		cp.Speed = ld.Speed
+	}
+	if !math.IsNaN(ld.Surge) {
+		cp.Surge = ld.Surge
+	}
+	if !math.IsNaN(ld.Surges) && ph.Duration > 0 {
+		cp.Surges = ld.Surges / ph.Duration
+	}
+	if !math.IsNaN(ld.Prf) {
+		cp.Prf = ld.Prf
+	}
+	return &cp
+}
+
 type Bom struct {
-	Components []*Component
+	Components []*Component `json:"components"`
 }
 
-func (bom *Bom) Sort(field string) {
-	sort.Sort(bom)
+// Sort sorts the components by the given field: name (the default if empty),
+// fit, class, block, package, type, or any numeric field (v, p, i, t, vp, vmax,
+// pmax, imax, tmax, vpmax, value). FIT is sorted in descending order (Pareto)
+// and the rest in ascending order, unless the field is prefixed with '+'
+// (ascending) or '-' (descending). Ties are sorted by name, so the result is
+// stable across runs. NaN values go last.
+func (bom *Bom) Sort(field string) error {
+
+	desc := false
+	field = strings.ToLower(strings.TrimSpace(field))
+
+	if field == "fit" {
+		desc = true
+	}
+	if strings.HasPrefix(field, "-") {
+		desc = true
+		field = field[1:]
+	} else if strings.HasPrefix(field, "+") {
+		desc = false
+		field = field[1:]
+	}
+
+	if field == "" || field == "name" {
+		if desc {
+			sort.Stable(sort.Reverse(bom))
+		} else {
+			sort.Stable(bom)
+		}
+		return nil
+	}
+
+	num := numField(field)
+	str := strField(field)
+	if num == nil && str == nil {
+		return errors.New("unknown sort field " + field)
+	}
+
+	sort.SliceStable(bom.Components, func(i, j int) bool {
+
+		a, b := bom.Components[i], bom.Components[j]
+		n := 0
+
+		if num != nil {
+			x, y := num(a), num(b)
+			if math.IsNaN(x) != math.IsNaN(y) {
+				return math.IsNaN(y) // NaN last, in both directions
+			}
+			if x < y {
+				n = -1
+			} else if x > y {
+				n = 1
+			}
+		} else {
+			n = strings.Compare(str(a), str(b))
+		}
+
+		if desc {
+			n = -n
+		}
+		if n == 0 {
+			return expandNumber(a.Name, 6) < expandNumber(b.Name, 6)
+		}
+		return n < 0
+	})
+
+	return nil
+}
+
+func numField(field string) func(c *Component) float64 {
+
+	switch field {
+	case "fit":
+		return func(c *Component) float64 { return c.FIT }
+	case "value":
+		return func(c *Component) float64 { return c.Value }
+	case "v":
+		return func(c *Component) float64 { return c.V }
+	case "vp":
+		return func(c *Component) float64 { return c.Vp }
+	case "p":
+		return func(c *Component) float64 { return c.P }
+	case "i":
+		return func(c *Component) float64 { return c.I }
+	case "t":
+		return func(c *Component) float64 { return c.T }
+	case "vmax":
+		return func(c *Component) float64 { return c.Vmax }
+	case "vpmax":
+		return func(c *Component) float64 { return c.Vpmax }
+	case "pmax":
+		return func(c *Component) float64 { return c.Pmax }
+	case "imax":
+		return func(c *Component) float64 { return c.Imax }
+	case "tmax":
+		return func(c *Component) float64 { return c.Tmax }
+	}
+	return nil
+}
+
+func strField(field string) func(c *Component) string {
+
+	switch field {
+	case "class":
+		return func(c *Component) string { return c.Class }
+	case "block":
+		return func(c *Component) string { return c.Block }
+	case "package":
+		return func(c *Component) string { return c.Package }
+	case "type":
+		return func(c *Component) string { return c.Type }
+	}
+	return nil
 }
 
 func (bom *Bom) Len() int { return len(bom.Components) }
@@ -100,6 +312,9 @@ func (bom *Bom) FromCsvs(files []string) error {
 
 	m := csv.ReadTyped(files)
 
+	var errs []error
+	var err error
+
 	for key, r := range m {
 
 		c := &Component{Name: key}
@@ -114,6 +329,9 @@ func (bom *Bom) FromCsvs(files []string) error {
 		if val, ok := r["block"]; ok {
 			c.Block = val
 		}
+		if val, ok := r["zone"]; ok {
+			c.Zone = val
+		}
 		if val, ok := r["tags"]; ok {
 			c.Tags = append(c.Tags, strings.Fields(val)...)
 		}
@@ -129,54 +347,172 @@ func (bom *Bom) FromCsvs(files []string) error {
 		if val, ok := r["package"]; ok {
 			c.Package = strings.ToUpper(val)
 		}
-		if val, ok := r["ndevices"]; ok {
-			c.N, _ = strconv.Atoi(val)
+		if val, ok := r["ndevices"]; ok && val != "" {
+			c.N, err = strconv.Atoi(val)
+			if err != nil {
+				errs = append(errs, errors.New(key+": ndevices: expected an integer ["+val+"]"))
+			}
 		}
-		if val, ok := r["npins"]; ok {
-			c.Np, _ = strconv.Atoi(val)
+		if val, ok := r["npins"]; ok && val != "" {
+			c.Np, err = strconv.Atoi(val)
+			if err != nil {
+				errs = append(errs, errors.New(key+": npins: expected an integer ["+val+"]"))
+			}
 		}
 		if val, ok := r["vmax"]; ok {
-			c.Vmax, _ = strconv.ParseFloat(val, 64)
+			c.Vmax = parseField(&errs, key, "vmax", val, "V", 0)
 		}
 		if val, ok := r["v"]; ok {
-			c.V, _ = strconv.ParseFloat(val, 64)
+			c.V = parseField(&errs, key, "v", val, "V", 0)
 		}
 		if val, ok := r["vpmax"]; ok {
-			c.Vpmax, _ = strconv.ParseFloat(val, 64)
+			c.Vpmax = parseField(&errs, key, "vpmax", val, "V", 0)
 		}
 		if val, ok := r["vp"]; ok {
-			c.Vp, _ = strconv.ParseFloat(val, 64)
+			c.Vp = parseField(&errs, key, "vp", val, "V", 0)
 		}
 		if val, ok := r["pmax"]; ok {
-			c.Pmax, _ = strconv.ParseFloat(val, 64)
+			c.Pmax = parseField(&errs, key, "pmax", val, "W", 0)
 		}
 		if val, ok := r["p"]; ok {
-			c.P, _ = strconv.ParseFloat(val, 64)
+			c.P = parseField(&errs, key, "p", val, "W", 0)
 		}
 		if val, ok := r["imax"]; ok {
-			c.Imax, _ = strconv.ParseFloat(val, 64)
+			c.Imax = parseField(&errs, key, "imax", val, "A", 0)
 		}
 		if val, ok := r["i"]; ok {
-			c.I, _ = strconv.ParseFloat(val, 64)
+			c.I = parseField(&errs, key, "i", val, "A", 0)
 		}
 		if val, ok := r["tmax"]; ok {
-			c.Tmax, _ = strconv.ParseFloat(val, 64)
+			c.Tmax = parseField(&errs, key, "tmax", val, "ºC", 0)
 		}
 		if val, ok := r["t"]; ok {
-			c.T, _ = strconv.ParseFloat(val, 64)
+			c.T = parseField(&errs, key, "t", val, "ºC", 0)
 		}
 		if val, ok := r["rtha"]; ok {
-			c.Rtha, _ = strconv.ParseFloat(val, 64)
+			c.Rtha = parseField(&errs, key, "rtha", val, "ºC/W", 0)
+		}
+		if val, ok := r["rca"]; ok {
+			c.Rca = parseField(&errs, key, "rca", val, "ºC/W", 0)
+		}
+		if val, ok := r["ops"]; ok {
+			c.Ops = parseField(&errs, key, "ops", val, "", 0)
+		}
+		if val, ok := r["duty"]; ok {
+			c.Duty = parseField(&errs, key, "duty", val, "", 0)
+		}
+		if val, ok := r["speed"]; ok {
+			c.Speed = parseField(&errs, key, "speed", val, "", 0)
+		}
+		if val, ok := r["cycles"]; ok {
+			c.Cycles = parseField(&errs, key, "cycles", val, "", 0)
+		}
+		if val, ok := r["ripple"]; ok {
+			c.Ripple = parseField(&errs, key, "ripple", val, "A", 0)
+		}
+		if val, ok := r["esr"]; ok {
+			c.Esr = parseField(&errs, key, "esr", val, "Ω", 0)
+		}
+		if val, ok := r["life"]; ok {
+			c.Life = parseField(&errs, key, "life", val, "h", 0)
+		}
+		if val, ok := r["node"]; ok {
+			c.Node = parseField(&errs, key, "node", val, "", 0)
+		}
+		if val, ok := r["vcore"]; ok {
+			c.Vcore = parseField(&errs, key, "vcore", val, "V", 0)
+		}
+		if val, ok := r["activity"]; ok {
+			c.Activity = parseField(&errs, key, "activity", val, "", 0)
+		}
+		if val, ok := r["prf"]; ok {
+			c.Prf = parseField(&errs, key, "prf", val, "W", 0)
+		}
+		if val, ok := r["prfmax"]; ok {
+			c.Prfmax = parseField(&errs, key, "prfmax", val, "W", 0)
+		}
+		if val, ok := r["freq"]; ok {
+			c.Freq = parseField(&errs, key, "freq", val, "Hz", 0)
+		}
+		if val, ok := r["dies"]; ok {
+			c.Dies = val
+		}
+		if val, ok := r["tref"]; ok {
+			c.Tref = parseField(&errs, key, "tref", val, "ºC", 0)
+		}
+		if val, ok := r["ip"]; ok {
+			c.Ip = parseField(&errs, key, "ip", val, "A", 0)
+		}
+		if val, ok := r["tp"]; ok {
+			c.Tp = parseField(&errs, key, "tp", val, "s", 0)
+		}
+		if val, ok := r["surge"]; ok {
+			c.Surge = parseField(&errs, key, "surge", val, "J", 0)
+		}
+		if val, ok := r["surges"]; ok {
+			c.Surges = parseField(&errs, key, "surges", val, "", 0)
+		}
+		if val, ok := r["emax"]; ok {
+			c.Emax = parseField(&errs, key, "emax", val, "J", 0)
+		}
+		if val, ok := r["vwithstand"]; ok {
+			c.Vwithstand = parseField(&errs, key, "vwithstand", val, "V", 0)
 		}
 		if val, ok := r["tc"]; ok {
-			c.TC, _ = strconv.ParseFloat(val, 64)
+			c.TC = parseField(&errs, key, "tc", val, "ppm/ºC", 0)
 		}
 
 	}
 
 	bom.Sort("")
 
-	return nil
+	return joinErrors(errs)
+}
+
+// LoadsFromCsv reads the working conditions per phase from a CSV file with
+// the fields name (component reference), phase, v, p, i, t, ops (number of
+// operations in the phase) and duty. Empty fields fall back to the values of
+// the component.
+func (bom *Bom) LoadsFromCsv(file string) error {
+
+	m, err := csvRead(file)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	comps := make(map[string]*Component)
+	for _, c := range bom.Components {
+		comps[strings.ToLower(c.Name)] = c
+	}
+
+	for _, r := range m {
+
+		c := comps[r["name"]]
+		if c == nil {
+			return errors.New("load for unknown component " + r["name"])
+		}
+
+		ld := NewLoad()
+		ld.V = parseField(&errs, r["name"], "v", r["v"], "V", math.NaN())
+		ld.P = parseField(&errs, r["name"], "p", r["p"], "W", math.NaN())
+		ld.I = parseField(&errs, r["name"], "i", r["i"], "A", math.NaN())
+		ld.T = parseField(&errs, r["name"], "t", r["t"], "ºC", math.NaN())
+		ld.Ops = parseField(&errs, r["name"], "ops", r["ops"], "", math.NaN())
+		ld.Duty = parseField(&errs, r["name"], "duty", r["duty"], "", math.NaN())
+		ld.Speed = parseField(&errs, r["name"], "speed", r["speed"], "", math.NaN())
+		ld.Surge = parseField(&errs, r["name"], "surge", r["surge"], "J", math.NaN())
+		ld.Surges = parseField(&errs, r["name"], "surges", r["surges"], "", math.NaN())
+		ld.Prf = parseField(&errs, r["name"], "prf", r["prf"], "W", math.NaN())
+
+		if c.Loads == nil {
+			c.Loads = make(map[string]*Load)
+		}
+		c.Loads[r["phase"]] = ld
+	}
+
+	return joinErrors(errs)
 }
 
 func (c *Component) ToCsv() string {
@@ -0,0 +1,146 @@
+package fides
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestInPhase(t *testing.T) {
+
+	ld := NewLoad()
+	ld.V = 12
+	ld.Ops = 100
+	ld.Surges = 10
+
+	c := &Component{V: 5, P: 0.1, Ops: 1, Surges: 2, Loads: map[string]*Load{"run": ld}}
+
+	tests := []struct {
+		phase       string
+		duration    float64
+		v, p        float64
+		ops, surges float64
+	}{
+		{"run", 50, 12, 0.1, 2, 0.2},
+		{"RUN", 50, 12, 0.1, 2, 0.2},
+		{"run", 0, 12, 0.1, 1, 2},
+		{"idle", 50, 5, 0.1, 1, 2},
+	}
+
+	for _, tt := range tests {
+		cp := c.InPhase(&Phase{Name: tt.phase, Duration: tt.duration})
+		if cp == c {
+			t.Errorf("%s: InPhase returned the component, not a copy", tt.phase)
+		}
+		if cp.V != tt.v || cp.P != tt.p || !near(cp.Ops, tt.ops) || !near(cp.Surges, tt.surges) {
+			t.Errorf("%s (%g h): v %g, p %g, ops %g, surges %g, want %g, %g, %g, %g", tt.phase, tt.duration,
+				cp.V, cp.P, cp.Ops, cp.Surges, tt.v, tt.p, tt.ops, tt.surges)
+		}
+	}
+
+	c.InPhase(&Phase{Name: "run", Duration: 50}).P = 1
+	if c.P != 0.1 {
+		t.Error("a change of the copy changed the component")
+	}
+}
+
+func TestLoadsFromCsv(t *testing.T) {
+
+	file := testFile(t, "loads.csv", "name, phase, v, p, ops\nR1, Run, 3V3, , 10\nr1, idle, 0, 0,\n")
+
+	bom := &Bom{Components: []*Component{{Name: "R1"}}}
+	if err := bom.LoadsFromCsv(file); err != nil {
+		t.Fatal(err)
+	}
+
+	ld := bom.Components[0].Loads["run"]
+	if ld == nil || ld.V != 3.3 || !math.IsNaN(ld.P) || ld.Ops != 10 || !math.IsNaN(ld.I) {
+		t.Errorf("load of phase run: %+v", ld)
+	}
+	ld = bom.Components[0].Loads["idle"]
+	if ld == nil || ld.V != 0 || ld.P != 0 || !math.IsNaN(ld.Ops) {
+		t.Errorf("load of phase idle: %+v", ld)
+	}
+
+	if err := bom.LoadsFromCsv(testFile(t, "loads.csv", "name, phase, v\nR2, run, 5\n")); err == nil {
+		t.Error("no error for a load of an unknown component")
+	}
+
+	if err := bom.LoadsFromCsv(testFile(t, "loads.csv", "name, phase, v\nR1, run, 5A\n")); err == nil {
+		t.Error("no error for a value in the wrong unit")
+	}
+}
+
+func TestEvaluatePerPhase(t *testing.T) {
+
+	m := testMission(40)
+	idle := *m.Phases[0]
+	idle.Name = "idle"
+	m.Phases[0].Duration = 4380
+	idle.Duration = 4380
+	m.Phases = append(m.Phases, &idle)
+
+	ld := NewLoad()
+	ld.P = 0.1
+	c := &Component{Class: "R", Tags: []string{"thick"}, Package: "0805", Tmax: 155, Pmax: 0.125, V: 1, Value: 1000,
+		Loads: map[string]*Load{"use": ld}}
+
+	r, err := Evaluate(c, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.PhaseFIT(0) <= r.PhaseFIT(1) {
+		t.Errorf("FIT of phase use (P = 0.1 W) %g, not above idle (P = 1 mW) %g", r.PhaseFIT(0), r.PhaseFIT(1))
+	}
+	if c.P != 0 {
+		t.Errorf("the evaluation set P = %g in the BOM", c.P)
+	}
+}
+
+func TestBomSort(t *testing.T) {
+
+	nan := math.NaN()
+
+	bom := &Bom{Components: []*Component{
+		{Name: "R10", Class: "R", Package: "0805", FIT: 2, V: 5},
+		{Name: "C2", Class: "C", Package: "0603", FIT: 3, V: nan},
+		{Name: "R2", Class: "R", Package: "0603", FIT: nan, V: 12},
+		{Name: "C1", Class: "C", Package: "0805", FIT: 3, V: 5},
+	}}
+
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"", "C1 C2 R2 R10"},
+		{"name", "C1 C2 R2 R10"},
+		{"-name", "R10 R2 C2 C1"},
+		{"fit", "C1 C2 R10 R2"},
+		{"+fit", "R10 C1 C2 R2"},
+		{"v", "C1 R10 R2 C2"},
+		{"-v", "R2 C1 R10 C2"},
+		{"class", "C1 C2 R2 R10"},
+		{"package", "C2 R2 C1 R10"},
+		{"-Package", "C1 R10 C2 R2"},
+	}
+
+	for _, tt := range tests {
+
+		if err := bom.Sort(tt.field); err != nil {
+			t.Errorf("Sort(%q): %v", tt.field, err)
+			continue
+		}
+
+		var names []string
+		for _, c := range bom.Components {
+			names = append(names, c.Name)
+		}
+		if got := strings.Join(names, " "); got != tt.want {
+			t.Errorf("Sort(%q) = %s, want %s", tt.field, got, tt.want)
+		}
+	}
+
+	if err := bom.Sort("color"); err == nil {
+		t.Error("no error for an unknown field")
+	}
+}
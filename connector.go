@@ -8,9 +8,13 @@ import (
 
 // PCB connectors, less that one insertion/year
 func ConnectorFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(ConnectorEval(comp, mission))
+}
+
+func ConnectorEval(comp *Component, mission *Mission) (*Result, error) {
 
 	if comp.Np < 1 {
-		return math.NaN(), errors.New("Connector with 0 contacts")
+		return nil, errors.New("Connector with 0 contacts")
 	}
 
 	piMounting := 10.0
@@ -21,45 +25,54 @@ func ConnectorFIT(comp *Component, mission *Mission) (float64, error) {
 	}
 
 	// Base FIT
-	fit := 0.1 * piMounting * math.Pow(float64(comp.Np), 0.5) * 0.2
-	var factor float64
+	r := newResult(0.1 * piMounting * math.Pow(float64(comp.Np), 0.5) * 0.2)
 
 	for _, ph := range mission.Phases {
 
+		// Working conditions in this phase
+		c := comp.InPhase(ph)
+		on := mission.IsOn(comp, ph)
+		tamb := mission.Tamb(comp, ph)
+
 		// General rule
-		if ph.Tamb+comp.T > comp.Tmax {
-			s := fmt.Sprintf("Using component above its Tmax %f (Tamb=%f, Td=%f)\n", comp.Tmax, ph.Tamb, comp.T)
-			return math.NaN(), errors.New(s)
+		if tamb+c.T > comp.Tmax {
+			s := fmt.Sprintf("Using component above its Tmax %f (Tamb=%f, Td=%f)\n", comp.Tmax, tamb, c.T)
+			return nil, errors.New(s)
 		}
 
-		// Thermal (0 if off)
-		pi := 0.58 * PiThermal(0.1, ph.Tamb+comp.T, ph.On)
-
-		// Thermal cycling
-		pi += 0.04 * PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax)
+		// Proportion of time in this phase
+		w := ph.Duration / mission.Ttotal
 
-		// Mechanical
-		pi += 0.05 * PiMech(ph.Grms)
+		pi := &Contribution{Phase: ph.Name, Weight: w}
 
-		// Humidity
-		pi += 0.13 * PiRH(0.8, ph.RH, ph.Tamb)
+		// Thermal (0 if off)
+		pi.Thermal = w * 0.58 * PiThermal(0.1, tamb+c.T, on)
 
-		// Chemical
-		pi += PiChemical(0.2, ph.SalinePollution, ph.AmbientPollution, ph.ZonePollution, ph.IP)
+		// Thermal cycling
+		pi.TCycling = w * 0.04 * PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax)
 
-		// Proportion of time in this phase
-		pi *= ph.Duration / mission.Ttotal
+		// Mechanical, humidity and chemical
+		contactTerms(pi, w, ph, tamb, ph.IP)
 
 		// Stress factors and sensibility
 		ifactor, err := PiInduced(comp, ph)
 		if err != nil {
-			return math.NaN(), err
+			return nil, err
 		}
-		pi *= ifactor
-
-		factor += pi
+		pi.Induced = ifactor
 
+		r.add(pi)
 	}
 
-	return fit * factor * PiPM() * PiProcess(), nil
+	return r, nil
+}
+
+// contactTerms sets the environmental terms of electrical contacts
+// (connectors, switches): mechanical, humidity and chemical pollution.
+// Sealed contacts are not exposed to pollution.
+func contactTerms(pi *Contribution, w float64, ph *Phase, tamb float64, sealed bool) {
+
+	pi.Mechanical = w * 0.05 * PiMech(ph.Grms)
+	pi.Humidity = w * 0.13 * PiRH(0.8, ph.RH, tamb)
+	pi.Chemical = w * PiChemical(0.2, ph.SalinePollution, ph.AmbientPollution, ph.ZonePollution, sealed)
 }
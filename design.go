@@ -1,10 +1,342 @@
 package fides
 
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Design is a board or a system. Besides its own components, it can contain
+// child designs: subassemblies and COTS boards with their own BOM, or
+// black-box modules (power supplies, radio modules) with a supplier FIT.
 type Design struct {
-	Components []*Component
-	Mission    *Mission
+	Name       string       `json:"name"`
+	Components []*Component `json:"components,omitempty"`
+
+	// Mission of the top design. In a child design, the conditions inside
+	// it (optional), referenced by Phases.
+	Mission *Mission `json:"mission,omitempty"`
+
+	// BOM files (as for Bom.FromCsvs) and mission file (CSV or JSON), read
+	// by LoadDesign. Paths are relative to the design file.
+	Files       []string `json:"bom,omitempty"`
+	MissionFile string   `json:"mission_file,omitempty"`
+
+	// Number of instances in the parent (default 1)
+	N int `json:"n,omitempty"`
+
+	// Supplier FIT of a black-box module, per operating hour at Tref (ºC).
+	// If Tref is 0 the FIT is not scaled with the temperature.
+	FIT  float64 `json:"fit,omitempty"`
+	Tref float64 `json:"tref,omitempty"`
+
+	// Phases maps the phases of the parent mission to the phases of the
+	// mission of this design (names are not case sensitive). Unmapped phases
+	// keep the conditions of the parent, in the block of the design.
+	Phases map[string]string `json:"phases,omitempty"`
+
+	// Block of the parent that contains this design, for its power domain
+	// and thermal zone (optional)
+	Block string `json:"block,omitempty"`
+
+	Children []*Design `json:"children,omitempty"`
 }
 
 func NewDesign() *Design {
 	return &Design{}
 }
+
+// DesignResult is the FIT of a design and of each of its children
+type DesignResult struct {
+	Name     string          `json:"name"`
+	N        int             `json:"n"`
+	FIT      float64         `json:"fit"` // One instance, including the children
+	Own      float64         `json:"own"` // Own components, or supplier FIT
+	Results  []*Evaluation   `json:"results,omitempty"`
+	Children []*DesignResult `json:"children,omitempty"`
+
+	components []*Component
+}
+
+// LoadDesign reads a design from a JSON file, with the BOM and mission files
+// of the design and its children.
+func LoadDesign(file string) (*Design, error) {
+
+	d := &Design{}
+	if err := readJson(file, d); err != nil {
+		return nil, err
+	}
+
+	if err := d.load(filepath.Dir(file)); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Design) load(dir string) error {
+
+	path := func(f string) string {
+		if filepath.IsAbs(f) {
+			return f
+		}
+		return filepath.Join(dir, f)
+	}
+
+	if len(d.Files) > 0 {
+		bom := &Bom{}
+		var files []string
+		for _, f := range d.Files {
+			files = append(files, path(f))
+		}
+		if err := bom.FromCsvs(files); err != nil {
+			return errors.New(d.Name + ": " + err.Error())
+		}
+		d.Components = append(d.Components, bom.Components...)
+	}
+
+	if d.MissionFile != "" {
+		d.Mission = &Mission{}
+		var err error
+		if strings.HasSuffix(d.MissionFile, ".json") {
+			err = d.Mission.FromJson(path(d.MissionFile))
+		} else {
+			err = d.Mission.FromCsv(path(d.MissionFile))
+		}
+		if err != nil {
+			return errors.New(d.Name + ": " + err.Error())
+		}
+	}
+
+	for _, child := range d.Children {
+		if err := child.load(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Evaluate calculates the FIT of the design and its children for the given
+// mission (the mission of the design if nil). Components that cannot be
+// evaluated are left out of the FIT and listed by DesignResult.Errors.
+func (d *Design) Evaluate(mission *Mission) (*DesignResult, error) {
+
+	if mission == nil {
+		mission = d.Mission
+	}
+	if mission == nil {
+		return nil, errors.New("design " + d.Name + " without mission")
+	}
+
+	r := &DesignResult{Name: d.Name, N: d.instances(), components: d.Components}
+
+	if len(d.Components) > 0 {
+		rep := NewReport(&Bom{Components: d.Components}, mission)
+		r.Own = rep.FIT
+		r.Results = rep.Results
+	} else {
+		r.Own = d.supplierFIT(mission)
+	}
+	r.FIT = r.Own
+
+	for _, child := range d.Children {
+
+		m, err := child.missionIn(mission)
+		if err != nil {
+			return nil, err
+		}
+
+		cr, err := child.Evaluate(m)
+		if err != nil {
+			return nil, err
+		}
+		r.Children = append(r.Children, cr)
+		r.FIT += cr.FIT * float64(cr.N)
+	}
+
+	return r, nil
+}
+
+func (d *Design) instances() int {
+	if d.N < 1 {
+		return 1
+	}
+	return d.N
+}
+
+// missionIn returns the mission of a child design: the phases of the parent,
+// with the power domain and the local ambient of the block of the design in
+// the parent (Mission.IsOn and Mission.Tamb), or with the conditions of the
+// mapped phases of the child mission. A mapped phase is on only if it is on in
+// the child mission and the block is powered in the parent. A design without
+// block and mission is evaluated in the mission of the parent.
+func (d *Design) missionIn(parent *Mission) (*Mission, error) {
+
+	if d.Mission == nil && d.Block == "" {
+		return parent, nil
+	}
+
+	m := &Mission{Ttotal: parent.Ttotal}
+	if d.Mission != nil {
+		m.Blocks = d.Mission.Blocks
+		m.Zones = d.Mission.Zones
+	}
+
+	comp := d.placeholder()
+
+	for _, ph := range parent.Phases {
+
+		on := parent.IsOn(comp, ph)
+
+		var cp Phase
+
+		name, ok := d.mappedPhase(ph.Name)
+		if ok {
+			src := d.Mission.phase(name)
+			if src == nil {
+				return nil, errors.New("phase " + name + " not found in the mission of " + d.Name)
+			}
+			cp = *src
+			cp.Name = ph.Name
+			cp.Duration = ph.Duration
+			cp.On = src.On && on
+		} else {
+			// The domains of the parent do not apply to the blocks of the
+			// child
+			cp = *ph
+			cp.On = on
+			cp.Tamb = parent.Tamb(comp, ph)
+			cp.Domains = nil
+		}
+
+		// The power domains of the child are off with its block
+		if !on {
+			cp.Domains = nil
+		}
+
+		m.Phases = append(m.Phases, &cp)
+	}
+
+	return m, nil
+}
+
+// mappedPhase returns the phase of the mission of the design mapped to a
+// phase of the parent. Phase names are not case sensitive, as in the loads
+// file.
+func (d *Design) mappedPhase(parent string) (string, bool) {
+	for from, to := range d.Phases {
+		if strings.EqualFold(from, parent) {
+			return to, true
+		}
+	}
+	return "", false
+}
+
+// placeholder returns a component that stands for the design in the parent,
+// to find its power domain and thermal zone.
+func (d *Design) placeholder() *Component {
+	return &Component{Name: d.Name, Block: d.Block}
+}
+
+// phase returns the phase with the given name (not case sensitive), or nil
+func (m *Mission) phase(name string) *Phase {
+	for _, ph := range m.Phases {
+		if strings.EqualFold(ph.Name, name) {
+			return ph
+		}
+	}
+	return nil
+}
+
+// supplierFIT returns the FIT of a black-box module over the mission. The
+// supplier FIT applies to the time the module is on (the power domain of its
+// block, as Mission.IsOn), scaled from Tref to the local ambient of each phase
+// with an activation energy of 0.7 eV.
+func (d *Design) supplierFIT(mission *Mission) float64 {
+
+	var fit float64
+
+	comp := d.placeholder()
+
+	for _, ph := range mission.Phases {
+
+		if !mission.IsOn(comp, ph) {
+			continue
+		}
+
+		k := 1.0
+		if d.Tref != 0 {
+			k = ArrheniusK(0.7, d.Tref+273, mission.Tamb(comp, ph)+273)
+		}
+		fit += ph.Duration / mission.Ttotal * d.FIT * k
+	}
+	return fit
+}
+
+// Errors returns the components of the design and its children that could not
+// be evaluated, as "design/child/NAME: error". Their FIT is not included in the
+// FIT of the designs.
+func (r *DesignResult) Errors() []string {
+	var ee []string
+	r.errors("", &ee)
+	return ee
+}
+
+func (r *DesignResult) errors(prefix string, ee *[]string) {
+
+	prefix += r.Name
+
+	for _, e := range r.Results {
+		if e.Error != "" {
+			*ee = append(*ee, prefix+"/"+strings.ToUpper(e.Name)+": "+e.Error)
+		}
+	}
+
+	for _, child := range r.Children {
+		child.errors(prefix+"/", ee)
+	}
+}
+
+// Rollup returns the FIT of each design in the tree, including its children
+// and all its instances, with the path of the design as name (top/child).
+// Count is the number of components, and Top the components of the design
+// itself with the highest FIT.
+func (r *DesignResult) Rollup(ntop int) []*Group {
+
+	var gg []*Group
+	r.rollup("", 1, r.FIT*float64(r.N), ntop, &gg)
+
+	sort.SliceStable(gg, func(i, j int) bool { return gg[i].Name < gg[j].Name })
+	return gg
+}
+
+func (r *DesignResult) rollup(prefix string, n int, total float64, ntop int, gg *[]*Group) *Group {
+
+	n *= r.N
+
+	g := &Group{Name: prefix + r.Name, FIT: r.FIT * float64(n)}
+	if total > 0 {
+		g.Share = g.FIT / total
+	}
+
+	for _, c := range r.components {
+		g.Count += n
+		if !math.IsNaN(c.FIT) {
+			g.Top = append(g.Top, c)
+		}
+	}
+	sort.SliceStable(g.Top, func(i, j int) bool { return g.Top[i].FIT > g.Top[j].FIT })
+	if len(g.Top) > ntop {
+		g.Top = g.Top[:ntop]
+	}
+
+	*gg = append(*gg, g)
+
+	for _, child := range r.Children {
+		cg := child.rollup(g.Name+"/", n, total, ntop, gg)
+		g.Count += cg.Count
+	}
+
+	return g
+}
@@ -0,0 +1,122 @@
+package fides
+
+import "testing"
+
+// designMission returns a mission with the block psu in the domain aux, which
+// is off in standby, and in a zone 30 ºC above the ambient.
+func designMission() *Mission {
+
+	m := testMission(40)
+	m.Phases[0].Duration = 6000
+	m.Phases[0].Domains = map[string]bool{"aux": true}
+
+	standby := *m.Phases[0]
+	standby.Name = "standby"
+	standby.Duration = 2760
+	standby.Domains = map[string]bool{"aux": false}
+	m.Phases = append(m.Phases, &standby)
+
+	m.Blocks = map[string]string{"psu": "aux"}
+	m.Zones = map[string]*Zone{"hot": {Name: "hot", Rise: 30, Blocks: []string{"psu"}}}
+	return m
+}
+
+func designResistor(block string) *Component {
+	return &Component{Name: "R1", Class: "R", Tags: []string{"thick"}, Package: "0805", Block: block,
+		Tmax: 155, Pmax: 0.125, P: 0.05, Value: 1000}
+}
+
+func TestDesignBlock(t *testing.T) {
+
+	m := designMission()
+
+	direct, err := FIT(designResistor("psu"), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outside, err := FIT(designResistor(""), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if near(direct, outside) {
+		t.Fatal("the block does not change the FIT of the resistor")
+	}
+
+	tests := []struct {
+		name string
+		d    *Design
+		want float64
+	}{
+		{"child in block", &Design{Name: "sub", Block: "psu", Components: []*Component{designResistor("")}}, direct},
+		{"child without block", &Design{Name: "sub", Components: []*Component{designResistor("")}}, outside},
+		{"black box", &Design{Name: "box", Block: "psu", FIT: 100}, 100 * 6000 / 8760.0},
+		{"black box with tref", &Design{Name: "box", Block: "psu", FIT: 100, Tref: 70}, 100 * 6000 / 8760.0},
+		{"black box outside", &Design{Name: "box", FIT: 100}, 100},
+	}
+
+	for _, tt := range tests {
+
+		top := &Design{Name: "top", Mission: m, Children: []*Design{tt.d}}
+		r, err := top.Evaluate(nil)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !near(r.FIT, tt.want) {
+			t.Errorf("%s: FIT = %g, want %g", tt.name, r.FIT, tt.want)
+		}
+	}
+}
+
+func TestDesignMissionIn(t *testing.T) {
+
+	m := designMission()
+
+	inside := &Mission{Ttotal: 8760, Phases: []*Phase{{Name: "Sealed", Duration: 1, On: true, Tamb: 20,
+		Domains: map[string]bool{"core": true}}}, Blocks: map[string]string{"cpu": "core"}}
+
+	tests := []struct {
+		name string
+		d    *Design
+		on   []bool
+		tamb []float64
+	}{
+		{"no block", &Design{Name: "sub"}, []bool{true, true}, []float64{40, 40}},
+		{"block", &Design{Name: "sub", Block: "PSU"}, []bool{true, false}, []float64{70, 40}},
+		{"mapped", &Design{Name: "sub", Block: "psu", Mission: inside, Phases: map[string]string{"USE": "sealed", "standby": "sealed"}},
+			[]bool{true, false}, []float64{20, 20}},
+		{"partly mapped", &Design{Name: "sub", Block: "psu", Mission: inside, Phases: map[string]string{"standby": "sealed"}},
+			[]bool{true, false}, []float64{70, 20}},
+	}
+
+	cpu := &Component{Block: "cpu"}
+
+	for _, tt := range tests {
+
+		cm, err := tt.d.missionIn(m)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if len(cm.Phases) != len(m.Phases) {
+			t.Errorf("%s: %d phases, want %d", tt.name, len(cm.Phases), len(m.Phases))
+			continue
+		}
+		for i, ph := range cm.Phases {
+			if ph.Name != m.Phases[i].Name || ph.Duration != m.Phases[i].Duration {
+				t.Errorf("%s: phase %s (%g h), want %s (%g h)", tt.name, ph.Name, ph.Duration, m.Phases[i].Name, m.Phases[i].Duration)
+			}
+			if ph.On != tt.on[i] || ph.Tamb != tt.tamb[i] {
+				t.Errorf("%s: phase %s on %v at %g ºC, want %v at %g ºC", tt.name, ph.Name, ph.On, ph.Tamb, tt.on[i], tt.tamb[i])
+			}
+			if cm.IsOn(cpu, ph) != tt.on[i] {
+				t.Errorf("%s: phase %s: a domain of the child is on with its block off", tt.name, ph.Name)
+			}
+		}
+	}
+
+	d := &Design{Name: "sub", Mission: inside, Phases: map[string]string{"use": "missing"}}
+	if _, err := d.missionIn(m); err == nil {
+		t.Error("no error for a phase not in the mission of the child")
+	}
+}
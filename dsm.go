@@ -0,0 +1,84 @@
+package fides
+
+import "math"
+
+// Wear-out of deep sub-micron ICs (90 nm and below), after the FIDES DSM
+// extension. Each mechanism has a reference rate at 105 ºC, the nominal core
+// voltage of the node and an activity factor of 0.2, for a 90 nm die, and is
+// accelerated by the core voltage (Vcore), the junction temperature, the
+// activity (Activity) and the node (Node, in nm):
+//
+//   - TDDB, gate oxide breakdown: (V/Vnom)^40, 0.7 eV
+//   - HCI, hot carriers: (V/Vnom)^20, activity, worse at low temperature (-0.1 eV)
+//   - NBTI, threshold shift of pMOS: (V/Vnom)^6, 0.15 eV, static stress (1-activity)
+//   - EM, electromigration of the interconnects: (V/Vnom * activity)^2, 0.9 eV
+//
+// The rates are in FIT, and are added to the wear-out of the result.
+
+var dsmMechanisms = []string{"tddb", "hci", "nbti", "em"}
+
+// Reference rates (FIT) of tddb, hci, nbti and em, their activation energy,
+// voltage exponent and node exponent
+var dsmRef = [][4]float64{
+	{5, 0.7, 40, 1},
+	{3, -0.1, 20, 0.5},
+	{5, 0.15, 6, 1},
+	{5, 0.9, 2, 1.5},
+}
+
+// isDsm returns true for ICs of 90 nm and below
+func isDsm(c *Component) bool {
+	return c.Node > 0 && c.Node <= 90
+}
+
+// dsmVnom returns the nominal core voltage of a technology node
+func dsmVnom(node float64) float64 {
+
+	switch {
+	case node >= 65:
+		return 1.2
+	case node >= 40:
+		return 1.1
+	case node >= 28:
+		return 1.0
+	case node >= 16:
+		return 0.8
+	}
+	return 0.75
+}
+
+// dsmWearout returns the wear-out rate (FIT) of each mechanism, in the order
+// of dsmMechanisms, at junction temperature tj.
+func dsmWearout(c *Component, tj float64) []float64 {
+
+	vnom := dsmVnom(c.Node)
+	v := c.Vcore
+	if v <= 0 {
+		v = vnom
+	}
+
+	activity := c.Activity
+	if activity <= 0 {
+		activity = 0.2
+	}
+
+	kv := v / vnom
+
+	fit := make([]float64, len(dsmMechanisms))
+
+	for i, ref := range dsmRef {
+
+		f := ref[0] * math.Pow(kv, ref[2]) * ArrheniusK(ref[1], 105+273, tj+273) * math.Pow(90/c.Node, ref[3])
+
+		switch dsmMechanisms[i] {
+		case "hci":
+			f *= activity / 0.2
+		case "nbti":
+			f *= (1 - activity) / 0.8
+		case "em":
+			f *= math.Pow(activity/0.2, 2)
+		}
+		fit[i] = f
+	}
+	return fit
+}
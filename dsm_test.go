@@ -0,0 +1,116 @@
+package fides
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDsmVnom(t *testing.T) {
+
+	tests := []struct {
+		node, want float64
+	}{
+		{90, 1.2},
+		{65, 1.2},
+		{45, 1.1},
+		{28, 1.0},
+		{16, 0.8},
+		{7, 0.75},
+	}
+
+	for _, tt := range tests {
+		if got := dsmVnom(tt.node); got != tt.want {
+			t.Errorf("dsmVnom(%g) = %g, want %g", tt.node, got, tt.want)
+		}
+	}
+}
+
+func TestIsDsm(t *testing.T) {
+
+	tests := []struct {
+		node float64
+		want bool
+	}{
+		{0, false},
+		{130, false},
+		{90, true},
+		{7, true},
+	}
+
+	for _, tt := range tests {
+		if got := isDsm(&Component{Class: "U", Node: tt.node}); got != tt.want {
+			t.Errorf("isDsm(node %g) = %v, want %v", tt.node, got, tt.want)
+		}
+	}
+}
+
+func TestDsmWearout(t *testing.T) {
+
+	// tddb, hci, nbti and em
+	tests := []struct {
+		name string
+		comp *Component
+		tj   float64
+		want []float64
+	}{
+		{"reference", &Component{Node: 90}, 105, []float64{5, 3, 5, 5}},
+		{"node 45", &Component{Node: 45}, 105, []float64{10, 3 * math.Sqrt2, 10, 5 * math.Pow(2, 1.5)}},
+		{"vcore +10%", &Component{Node: 90, Vcore: 1.32}, 105,
+			[]float64{5 * math.Pow(1.1, 40), 3 * math.Pow(1.1, 20), 5 * math.Pow(1.1, 6), 5 * 1.21}},
+		{"activity 0.4", &Component{Node: 90, Activity: 0.4}, 105, []float64{5, 6, 3.75, 20}},
+		{"cold", &Component{Node: 90}, 25, []float64{
+			5 * ArrheniusK(0.7, 378, 298), 3 * ArrheniusK(-0.1, 378, 298), 5 * ArrheniusK(0.15, 378, 298), 5 * ArrheniusK(0.9, 378, 298)}},
+	}
+
+	for _, tt := range tests {
+		got := dsmWearout(tt.comp, tt.tj)
+		for i := range tt.want {
+			if !near(got[i], tt.want[i]) {
+				t.Errorf("%s: %s %g FIT, want %g", tt.name, dsmMechanisms[i], got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestSemiconductorDsm(t *testing.T) {
+
+	m := testMission(105)
+
+	comp := &Component{Class: "U", Tags: []string{"digital"}, Package: "SOT23-6", Tmax: 150, Node: 90}
+	r, err := SemiconductorEval(comp, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !near(r.Wearout(), 18) {
+		t.Errorf("wear-out %g FIT, want 18", r.Wearout())
+	}
+	if len(r.Mechanisms) != 4 || !near(r.Mechanisms["hci"], 3) {
+		t.Errorf("mechanisms %v", r.Mechanisms)
+	}
+	if len(r.Warnings) > 0 {
+		t.Errorf("warnings %v", r.Warnings)
+	}
+
+	// Overvoltage
+	comp.Vcore = 1.4
+	r, err = SemiconductorEval(comp, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Warnings) != 1 || !strings.Contains(r.Warnings[0], "more than 10% above the nominal") {
+		t.Errorf("warnings %v", r.Warnings)
+	}
+
+	// No wear-out when off, or above 90 nm
+	off := testMission(105)
+	off.Phases[0].On = false
+	comp.Vcore = 0
+	if r, err = SemiconductorEval(comp, off); err != nil || r.Wearout() != 0 {
+		t.Errorf("off: wear-out %v, err %v", r, err)
+	}
+	comp.Node = 130
+	if r, err = SemiconductorEval(comp, m); err != nil || r.Wearout() != 0 || r.Mechanisms != nil {
+		t.Errorf("130 nm: wear-out %v, err %v", r, err)
+	}
+}
@@ -0,0 +1,126 @@
+package fides
+
+import (
+	"errors"
+	"math"
+)
+
+// FanFIT returns the FIT of a fan or blower (class FAN)
+func FanFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(FanEval(comp, mission))
+}
+
+// FanEval returns the detailed FIT of a fan: the constant rate of the motor
+// and its driver, and the wear-out of the bearings.
+//
+// Life is the rated L10 life (h) at the reference temperature Tref (default
+// 40 ºC) and Speed the speed as a fraction of the rated speed (default 1). The
+// L10 life is halved every 10 to 15 ºC above Tref, depending on the bearing,
+// and is used only while the fan runs. The wear-out FIT is 0.1/L10, and the
+// end of life is reported as for other wear-out models.
+func FanEval(comp *Component, mission *Mission) (*Result, error) {
+
+	bearing := fanBearing(comp.Tags)
+	fit, lth, ltc, lm, lrh, l10, halving := lbase_fan(bearing)
+
+	if comp.Life > 0 {
+		l10 = comp.Life
+	}
+	tref := comp.Tref
+	if tref == 0 {
+		tref = 40
+	}
+
+	r := newResult(fit)
+
+	// Fraction of the L10 life used by the mission
+	used := 0.0
+
+	for _, ph := range mission.Phases {
+
+		// Working conditions in this phase
+		c := comp.InPhase(ph)
+		on := mission.IsOn(comp, ph)
+		tamb := mission.Tamb(comp, ph)
+
+		if tamb > comp.Tmax {
+			return nil, errors.New("Using component above its Tmax")
+		}
+
+		speed := 1.0
+		if c.Speed > 0 {
+			speed = c.Speed
+		}
+
+		// Proportion of time in this phase
+		w := ph.Duration / mission.Ttotal
+
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+
+		// Motor driver and windings
+		pi.Thermal = w * lth * PiThermal(0.7, tamb, on)
+		pi.TCycling = w * ltc * PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax)
+		pi.Mechanical = w * lm * PiMech(ph.Grms)
+		pi.Humidity = w * lrh * PiRH(0.8, ph.RH, tamb)
+
+		// Stress factors and sensibility
+		ifactor, err := PiInduced(comp, ph)
+		if err != nil {
+			return nil, err
+		}
+		pi.Induced = ifactor
+
+		// Bearings
+		if on {
+			life := fanLife(l10, halving, tamb-tref, speed)
+			pi.Wearout = w * 1e9 * 0.1 / life
+			used += ph.Duration / life
+		}
+
+		r.add(pi)
+	}
+
+	if used > 0 {
+		r.Life = mission.Ttotal / used
+		if r.Life < mission.Ttotal {
+			r.warn("L10 life of the fan (%.0f h) shorter than the mission (%.0f h)", r.Life, mission.Ttotal)
+		}
+	}
+
+	return r, nil
+}
+
+// fanBearing returns ball (default), sleeve or fluid (fluid dynamic or
+// hydraulic bearings)
+func fanBearing(tags []string) string {
+
+	for _, tag := range tags {
+		switch tag {
+		case "sleeve":
+			return "sleeve"
+		case "fluid", "fdb", "hydro":
+			return "fluid"
+		}
+	}
+	return "ball"
+}
+
+// Returns l0, lth, ltc, lmech, lrh (motor driver and windings), the rated L10
+// life (h) at 40 ºC and the temperature rise (ºC) that halves it
+func lbase_fan(bearing string) (float64, float64, float64, float64, float64, float64, float64) {
+
+	switch bearing {
+	case "sleeve":
+		return 5, 0.6, 0.1, 0.2, 0.1, 30000, 10
+	case "fluid":
+		return 5, 0.6, 0.1, 0.2, 0.1, 70000, 12
+	}
+	return 5, 0.6, 0.1, 0.2, 0.1, 60000, 15
+}
+
+// fanLife returns the L10 life at dt ºC over the reference temperature and at
+// the given fraction of the rated speed. The life of the bearings is a number
+// of revolutions, so it grows as the fan runs slower.
+func fanLife(l10, halving, dt, speed float64) float64 {
+	return l10 / math.Pow(2, dt/halving) / speed
+}
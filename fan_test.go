@@ -0,0 +1,95 @@
+package fides
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFanBearing(t *testing.T) {
+
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{nil, "ball"},
+		{[]string{"sleeve"}, "sleeve"},
+		{[]string{"fdb"}, "fluid"},
+		{[]string{"hydro"}, "fluid"},
+	}
+
+	for _, tt := range tests {
+		if got := fanBearing(tt.tags); got != tt.want {
+			t.Errorf("fanBearing(%v) = %s, want %s", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestFanLife(t *testing.T) {
+
+	tests := []struct {
+		l10, halving, dt, speed float64
+		want                    float64
+	}{
+		{60000, 15, 0, 1, 60000},
+		{60000, 15, 15, 1, 30000},
+		{30000, 10, 20, 1, 7500},
+		{60000, 15, -15, 1, 120000},
+		{60000, 15, 0, 0.5, 120000},
+	}
+
+	for _, tt := range tests {
+		if got := fanLife(tt.l10, tt.halving, tt.dt, tt.speed); !near(got, tt.want) {
+			t.Errorf("fanLife(%g, %g, %g, %g) = %g, want %g", tt.l10, tt.halving, tt.dt, tt.speed, got, tt.want)
+		}
+	}
+}
+
+func TestFanEval(t *testing.T) {
+
+	tests := []struct {
+		name string
+		tamb float64
+		comp *Component
+		life float64
+		warn string
+	}{
+		{"ball at tref", 40, &Component{Class: "FAN", Tmax: 70}, 60000, ""},
+		{"sleeve, hot", 50, &Component{Class: "FAN", Tags: []string{"sleeve"}, Tmax: 70}, 15000, ""},
+		{"given life and tref", 25, &Component{Class: "FAN", Tmax: 70, Life: 50000, Tref: 25}, 50000, ""},
+		{"half speed", 40, &Component{Class: "FAN", Tmax: 70, Speed: 0.5}, 120000, ""},
+		{"short life", 40, &Component{Class: "FAN", Tmax: 70, Life: 5000}, 5000, "shorter than the mission"},
+	}
+
+	for _, tt := range tests {
+
+		r, err := FanEval(tt.comp, testMission(tt.tamb))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !near(r.Life, tt.life) {
+			t.Errorf("%s: life %g, want %g", tt.name, r.Life, tt.life)
+		}
+		if !near(r.Wearout(), 1e9*0.1/tt.life) {
+			t.Errorf("%s: wear-out %g FIT, want %g", tt.name, r.Wearout(), 1e9*0.1/tt.life)
+		}
+		warnings := strings.Join(r.Warnings, "; ")
+		if tt.warn == "" && warnings != "" || !strings.Contains(warnings, tt.warn) {
+			t.Errorf("%s: warnings %q, want %q", tt.name, warnings, tt.warn)
+		}
+	}
+
+	// No wear-out of the bearings while the fan is off
+	m := testMission(40)
+	m.Phases[0].On = false
+	r, err := FanEval(&Component{Class: "FAN", Tmax: 70}, m)
+	if err != nil {
+		t.Errorf("fan off: %v", err)
+	} else if r.Wearout() != 0 || r.Life != 0 {
+		t.Errorf("fan off: wear-out %g, life %g, want 0", r.Wearout(), r.Life)
+	}
+
+	if _, err := FanEval(&Component{Class: "FAN", Tmax: 70}, testMission(80)); err == nil {
+		t.Error("no error above Tmax")
+	}
+}
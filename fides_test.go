@@ -0,0 +1,29 @@
+package fides
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testMission returns a mission of one year in a single phase, at tamb and
+// with the equipment on.
+func testMission(tamb float64) *Mission {
+	ph := &Phase{Name: "use", Duration: 8760, On: true, Tamb: tamb, RH: 50, Grms: 0.5,
+		NCycles: 365, CycleDuration: 8, Tdelta: 10, Tmax: tamb + 10, AppFactor: 4.8}
+	return &Mission{Ttotal: 8760, Phases: []*Phase{ph}}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
+
+// testFile writes data to a temporary file and returns its name
+func testFile(t *testing.T, name, data string) string {
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
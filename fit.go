@@ -7,36 +7,81 @@ import (
 )
 
 func FIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(Evaluate(comp, mission))
+}
+
+// Evaluate calculates the FIT of a component and returns the detailed result,
+// with the contribution of each phase and mechanism.
+func Evaluate(comp *Component, mission *Mission) (*Result, error) {
 
 	// Mandatory attribues:
 	// - Tmax
 	if comp.Tmax == 0 || math.IsNaN(comp.Tmax) {
-		return math.NaN(), errors.New("Tmax (max temperature of component) not set")
+		return nil, errors.New("Tmax (max temperature of component) not set")
+	}
+
+	if comp.Zone != "" && mission.Zone(comp) == nil {
+		return nil, errors.New("unknown thermal zone " + comp.Zone)
 	}
 
 	class := strings.ToUpper(comp.Class)
 
+	if isProtection(comp) {
+		return ProtectionEval(comp, mission)
+	}
+
 	switch class {
 
 	case "U":
+		if isHybrid(comp) {
+			return HybridEval(comp, mission)
+		}
 		if contains(comp.Tags, "opto") {
-			return OptoFIT(comp, mission)
+			return OptoEval(comp, mission)
 		}
 		fallthrough
-	case "Q", "D":
-		return SemiconductorFIT(comp, mission)
+	case "Q":
+		return SemiconductorEval(comp, mission)
+	case "D":
+		if contains(comp.Tags, "led") {
+			return LedEval(comp, mission)
+		}
+		return SemiconductorEval(comp, mission)
 	case "R":
-		return ResistorFIT(comp, mission)
+		if contains(comp.Tags, "fuse") {
+			return FuseEval(comp, mission)
+		}
+		return ResistorEval(comp, mission)
+	case "F":
+		return FuseEval(comp, mission)
 	case "C":
-		return CapacitorFIT(comp, mission)
+		return CapacitorEval(comp, mission)
 	case "L":
-		return InductorFIT(comp, mission)
+		return InductorEval(comp, mission)
 	case "J":
-		return ConnectorFIT(comp, mission)
+		return ConnectorEval(comp, mission)
 	case "X":
-		return PiezoFIT(comp, mission)
+		return PiezoEval(comp, mission)
+	case "RL":
+		return RelayEval(comp, mission)
+	case "SW":
+		return SwitchEval(comp, mission)
+	case "BT":
+		return BatteryEval(comp, mission)
+	case "FAN":
+		return FanEval(comp, mission)
+	case "RF":
+		return RfEval(comp, mission)
 	default:
-		return math.NaN(), errors.New("unsupported component type " + class)
+		return nil, errors.New("unsupported component type " + class)
+
+	}
+}
 
+// fitOf returns the FIT of a result, or NaN in case of error
+func fitOf(r *Result, err error) (float64, error) {
+	if err != nil {
+		return math.NaN(), err
 	}
+	return r.FIT, nil
 }
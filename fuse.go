@@ -0,0 +1,117 @@
+package fides
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// FuseFIT returns the FIT of a fuse (class F, or class R with tag fuse)
+func FuseFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(FuseEval(comp, mission))
+}
+
+// FuseEval returns the detailed FIT of a fuse. Imax is the rated current and
+// I the operating current.
+//
+// The operating current is checked against the derating curve of the fuse
+// type, and the temperature of the element is estimated from the current
+// ratio (a rise of 40 ºC at rated current).
+func FuseEval(comp *Component, mission *Mission) (*Result, error) {
+
+	if comp.Imax == 0 || math.IsNaN(comp.Imax) {
+		return nil, errors.New("rated current Imax not set")
+	}
+	if comp.I == 0 || math.IsNaN(comp.I) {
+		return nil, errors.New("working I not set")
+	}
+
+	ftype := fuseType(comp.Tags)
+	fit, ea, lth, ltc, lm, lrh := lbase_fuse(ftype)
+
+	r := newResult(fit)
+
+	for _, ph := range mission.Phases {
+
+		// Working conditions in this phase
+		c := comp.InPhase(ph)
+		on := mission.IsOn(comp, ph)
+		tamb := mission.Tamb(comp, ph)
+
+		if tamb > comp.Tmax {
+			return nil, errors.New("Using component above its Tmax")
+		}
+
+		ratio := c.I / comp.Imax
+		if on {
+			if max := fuseDerating(ftype, tamb); ratio > max {
+				s := fmt.Sprintf("Fuse current above its derating curve: I/Imax = %.2f, max %.2f at %.1f ºC", ratio, max, tamb)
+				return nil, errors.New(s)
+			}
+		}
+
+		// Proportion of time in this phase
+		w := ph.Duration / mission.Ttotal
+
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+		if on {
+			pi.Thermal = w * lth * Arrhenius25(ea, tamb+40*ratio*ratio)
+		}
+		pi.TCycling = w * ltc * PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax)
+		pi.Humidity = w * lrh * PiRH2(0.9, ph.RH, tamb, on)
+		pi.Mechanical = w * lm * PiMech(ph.Grms)
+
+		// Stress factors and sensibility
+		ifactor, err := PiInduced(comp, ph)
+		if err != nil {
+			return nil, err
+		}
+		pi.Induced = ifactor
+
+		r.add(pi)
+	}
+
+	return r, nil
+}
+
+// fuseType returns chip (SMD, default), cartridge or ptc (resettable)
+func fuseType(tags []string) string {
+
+	if contains(tags, "ptc") || contains(tags, "pptc") || contains(tags, "resettable") || contains(tags, "polyfuse") {
+		return "ptc"
+	}
+	if contains(tags, "cartridge") || contains(tags, "tht") {
+		return "cartridge"
+	}
+	return "chip"
+}
+
+// Returns l0, ea, lth, ltc, lmech, lrh
+func lbase_fuse(ftype string) (float64, float64, float64, float64, float64, float64) {
+
+	switch ftype {
+	case "ptc":
+		return 0.2, 0.3, 0.5, 0.35, 0.05, 0.1
+	case "cartridge":
+		// Fuse clips are sensitive to vibrations
+		return 0.1, 0.15, 0.3, 0.3, 0.3, 0.1
+	}
+	return 0.05, 0.15, 0.4, 0.45, 0.05, 0.1
+}
+
+// fuseDerating returns the maximum operating current ratio (I/Imax) at the
+// given temperature: 75% of the rating at 25 ºC, reduced by 0.5%/ºC (1%/ºC
+// for PTC resettable fuses) above 25 ºC.
+func fuseDerating(ftype string, temp float64) float64 {
+
+	d := 0.005
+	if ftype == "ptc" {
+		d = 0.01
+	}
+
+	k := 1.0
+	if temp > 25 {
+		k = math.Max(1-d*(temp-25), 0)
+	}
+	return 0.75 * k
+}
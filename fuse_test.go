@@ -0,0 +1,81 @@
+package fides
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFuseDerating(t *testing.T) {
+
+	tests := []struct {
+		ftype string
+		temp  float64
+		want  float64
+	}{
+		{"chip", 0, 0.75},
+		{"chip", 25, 0.75},
+		{"chip", 85, 0.75 * 0.7},
+		{"cartridge", 45, 0.75 * 0.9},
+		{"ptc", 25, 0.75},
+		{"ptc", 85, 0.75 * 0.4},
+		{"ptc", 150, 0},
+	}
+
+	for _, tt := range tests {
+		if got := fuseDerating(tt.ftype, tt.temp); !near(got, tt.want) {
+			t.Errorf("fuseDerating(%s, %g) = %g, want %g", tt.ftype, tt.temp, got, tt.want)
+		}
+	}
+}
+
+func TestFuseType(t *testing.T) {
+
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{nil, "chip"},
+		{[]string{"fuse", "chip"}, "chip"},
+		{[]string{"cartridge"}, "cartridge"},
+		{[]string{"tht"}, "cartridge"},
+		{[]string{"resettable"}, "ptc"},
+		{[]string{"polyfuse", "tht"}, "ptc"},
+	}
+
+	for _, tt := range tests {
+		if got := fuseType(tt.tags); got != tt.want {
+			t.Errorf("fuseType(%v) = %s, want %s", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestFuseEval(t *testing.T) {
+
+	m := testMission(40)
+
+	tests := []struct {
+		name string
+		comp *Component
+		err  string
+	}{
+		{"chip", &Component{Class: "F", Tmax: 125, Imax: 2, I: 1}, ""},
+		{"resistor tagged fuse", &Component{Class: "R", Tags: []string{"fuse"}, Tmax: 125, Imax: 2, I: 1}, ""},
+		{"no rating", &Component{Class: "F", Tmax: 125, I: 1}, "Imax"},
+		{"no current", &Component{Class: "F", Tmax: 125, Imax: 2}, "working I"},
+		{"above derating", &Component{Class: "F", Tmax: 125, Imax: 2, I: 1.5}, "derating"},
+		{"above tmax", &Component{Class: "F", Tmax: 30, Imax: 2, I: 1}, "Tmax"},
+	}
+
+	for _, tt := range tests {
+		r, err := Evaluate(tt.comp, m)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: error %v, want %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || r.FIT <= 0 {
+			t.Errorf("%s: FIT %v, %v", tt.name, r, err)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package fides
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// HybridFIT returns the FIT of a hybrid, SiP or multi-chip module (class U,
+// tag hybrid, sip or mcm)
+func HybridFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(HybridEval(comp, mission))
+}
+
+// HybridEval returns the detailed FIT of a hybrid or multi-chip module. The
+// dies are given in Dies as a list of categories with an optional count and
+// rating ("microcontroller dram:2 igbt:2@50W diode@10A"). Each die adds its
+// own Lchip_th and the die attach and wire bond terms, which depend on the
+// substrate. Dies without a rating take the Pmax and Imax of the module, which
+// select the power rates of transistors and diodes. The package terms come
+// from the package database, and the encapsulation changes the humidity
+// terms.
+func HybridEval(comp *Component, mission *Mission) (*Result, error) {
+
+	dies, err := parseDies(comp.Dies)
+	if err != nil {
+		return nil, err
+	}
+	if len(dies) == 0 {
+		return nil, errors.New("hybrid without dies")
+	}
+
+	// Chips
+	var lth, ndies float64
+	for _, d := range dies {
+		chip := &Component{Class: d.Class, Tags: []string{d.Tag}, Pmax: d.Pmax, Imax: d.Imax}
+		if d.Pmax == 0 && d.Imax == 0 {
+			chip.Pmax, chip.Imax = comp.Pmax, comp.Imax
+		}
+		l := Lchip_th(chip)
+		if l < 0 {
+			return nil, errors.New("Missing data for lchip(th) calculation of die " + d.Tag)
+		}
+		lth += l * float64(d.N)
+		ndies += float64(d.N)
+	}
+
+	ksub := hybridSubstrate(comp.Tags)
+	ltc_chip := 0.021 * ndies * ksub
+	lm_chip := 0.011 * ndies
+
+	p := NewPackage(comp.Package)
+	lrh, ltc, lts, lm := p.FitBase()
+	if lrh < 0 || math.IsNaN(lrh) {
+		return nil, errors.New("Missing data for lpkg(rh,tc...) calculation for package: [" + p.Name + "]")
+	}
+
+	// Encapsulation
+	switch {
+	case contains(comp.Tags, "hermetic"):
+		lrh *= 0.1
+	case contains(comp.Tags, "glob"):
+		lrh *= 1.5
+	}
+
+	r := newResult(1)
+
+	for _, ph := range mission.Phases {
+
+		// Working conditions in this phase
+		c := comp.InPhase(ph)
+		on := mission.IsOn(comp, ph)
+		tamb := mission.Tamb(comp, ph)
+
+		tj, err := phaseTj(c, p, tamb, on)
+		if err != nil {
+			return nil, err
+		}
+
+		// Proportion of time in this phase
+		w := ph.Duration / mission.Ttotal
+
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+		pi.Thermal = w * lth * PiThermal(0.7, tj, on)
+		pi.TCycling = w * (ltc*PiTCCase(ph.NCycles, ph.Duration, ph.Tdelta, ph.Tmax) +
+			(lts+ltc_chip)*PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax))
+		pi.Humidity = w * lrh * PiRH2(0.9, ph.RH, tamb, on)
+		pi.Mechanical = w * (lm + lm_chip) * PiMech(ph.Grms)
+
+		// Stress factors and sensibility
+		ifactor, err := PiInduced(comp, ph)
+		if err != nil {
+			return nil, err
+		}
+		pi.Induced = ifactor
+
+		r.add(pi)
+	}
+
+	return r, nil
+}
+
+// isHybrid returns true for hybrids, SiPs and multi-chip modules
+func isHybrid(c *Component) bool {
+	return contains(c.Tags, "hybrid") || contains(c.Tags, "sip") || contains(c.Tags, "mcm")
+}
+
+// Die is one or more identical dies in a hybrid
+type Die struct {
+	Class string // U, Q or D, from the category
+	Tag   string // Category, as the tags of Lchip_th
+	N     int
+	Pmax  float64 // Rating of the die (optional)
+	Imax  float64
+}
+
+// Die categories per class, as the tags of Lchip_th
+var dieCategories = map[string][]string{
+	"U": {"digital", "analog", "mixed", "fpga", "cpld", "pal", "microprocessor", "microcontroller", "dsp", "complex",
+		"flash", "eprom", "eeprom", "sram", "dram", "opto"},
+	"Q": {"transistor", "mos", "mosfet", "jfet", "igbt", "gan", "gaas", "triac", "thyristor"},
+	"D": {"diode", "zener", "tvs"},
+}
+
+// parseDies reads a list of dies as "category[:count][@rating] ...". The
+// categories are in dieCategories. The rating is a power (Pmax, "@50W") or a
+// current (Imax, "@10A").
+func parseDies(s string) ([]*Die, error) {
+
+	var dies []*Die
+
+	for _, f := range strings.Fields(strings.ToLower(s)) {
+
+		d := &Die{Tag: f, N: 1}
+
+		if i := strings.Index(f, "@"); i >= 0 {
+			rating := f[i+1:]
+			if v, err := ParseUnit(rating, "W"); err == nil && strings.HasSuffix(rating, "w") {
+				d.Pmax = v
+			} else if v, err := ParseUnit(rating, "A"); err == nil && strings.HasSuffix(rating, "a") {
+				d.Imax = v
+			} else {
+				return nil, errors.New("wrong die rating in " + f + " (expected W or A)")
+			}
+			f = f[:i]
+			d.Tag = f
+		}
+
+		if i := strings.Index(f, ":"); i >= 0 {
+			n, err := strconv.Atoi(f[i+1:])
+			if err != nil || n < 1 {
+				return nil, errors.New("wrong die count in " + f)
+			}
+			d.Tag, d.N = f[:i], n
+		}
+
+		for class, cc := range dieCategories {
+			if contains(cc, d.Tag) {
+				d.Class = class
+			}
+		}
+		if d.Class == "" {
+			return nil, errors.New("unknown die category " + d.Tag)
+		}
+
+		dies = append(dies, d)
+	}
+
+	return dies, nil
+}
+
+// hybridSubstrate returns the factor of the die attach and wire bond thermal
+// cycling term: ceramic (thick film, LTCC; default) 1, laminate (organic)
+// 1.5 and silicon interposer 0.5.
+func hybridSubstrate(tags []string) float64 {
+
+	if contains(tags, "laminate") || contains(tags, "organic") {
+		return 1.5
+	}
+	if contains(tags, "silicon") || contains(tags, "interposer") {
+		return 0.5
+	}
+	return 1
+}
@@ -0,0 +1,131 @@
+package fides
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDies(t *testing.T) {
+
+	tests := []struct {
+		in   string
+		want []Die
+		err  string
+	}{
+		{"", nil, ""},
+		{"microcontroller", []Die{{"U", "microcontroller", 1, 0, 0}}, ""},
+		{"DRAM:2 igbt:2@50W diode@10A", []Die{{"U", "dram", 2, 0, 0}, {"Q", "igbt", 2, 50, 0}, {"D", "diode", 1, 0, 10}}, ""},
+		{"mos@500mw", []Die{{"Q", "mos", 1, 0.5, 0}}, ""},
+		{"cpu", nil, "unknown die category cpu"},
+		{"sram:0", nil, "wrong die count"},
+		{"sram:x", nil, "wrong die count"},
+		{"mos@5v", nil, "wrong die rating"},
+	}
+
+	for _, tt := range tests {
+
+		dies, err := parseDies(tt.in)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("parseDies(%q): error %v, want %q", tt.in, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseDies(%q): %v", tt.in, err)
+			continue
+		}
+		if len(dies) != len(tt.want) {
+			t.Errorf("parseDies(%q): %d dies, want %d", tt.in, len(dies), len(tt.want))
+			continue
+		}
+		for i, d := range dies {
+			if *d != tt.want[i] {
+				t.Errorf("parseDies(%q): die %d is %+v, want %+v", tt.in, i, *d, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestHybridSubstrate(t *testing.T) {
+
+	tests := []struct {
+		tags []string
+		want float64
+	}{
+		{[]string{"hybrid"}, 1},
+		{[]string{"sip", "laminate"}, 1.5},
+		{[]string{"mcm", "interposer"}, 0.5},
+	}
+
+	for _, tt := range tests {
+		if got := hybridSubstrate(tt.tags); got != tt.want {
+			t.Errorf("hybridSubstrate(%v) = %g, want %g", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestHybridEval(t *testing.T) {
+
+	m := testMission(40)
+
+	// The thermal term is the sum of the Lchip_th of the dies
+	tests := []struct {
+		dies string
+		lth  float64
+	}{
+		{"microcontroller", 0.075},
+		{"microcontroller dram:2", 0.075 + 2*0.047},
+		{"digital:4 zener", 4*0.021 + 0.008},
+	}
+
+	for _, tt := range tests {
+
+		comp := &Component{Class: "U", Tags: []string{"hybrid"}, Package: "SOT23-6", Tmax: 125, Dies: tt.dies}
+		r, err := HybridEval(comp, m)
+		if err != nil {
+			t.Errorf("%s: %v", tt.dies, err)
+			continue
+		}
+		want := tt.lth * PiThermal(0.7, 40, true)
+		if !near(r.Thermal(), want) {
+			t.Errorf("%s: thermal %g, want %g", tt.dies, r.Thermal(), want)
+		}
+	}
+
+	// Substrate and encapsulation (the humidity term counts only when off)
+	off := testMission(40)
+	off.Phases[0].On = false
+	fit := func(tags ...string) (*Result, error) {
+		comp := &Component{Class: "U", Tags: append([]string{"hybrid"}, tags...), Package: "SOT23-6", Tmax: 125, Dies: "sram:2"}
+		return HybridEval(comp, off)
+	}
+	ceramic, _ := fit()
+	laminate, _ := fit("laminate")
+	hermetic, _ := fit("hermetic")
+	if ceramic == nil || laminate == nil || hermetic == nil {
+		t.Fatal("hybrid not evaluated")
+	}
+	if laminate.TCycling() <= ceramic.TCycling() {
+		t.Errorf("laminate thermal cycling %g, not above ceramic %g", laminate.TCycling(), ceramic.TCycling())
+	}
+	if ceramic.Humidity() == 0 || !near(hermetic.Humidity(), 0.1*ceramic.Humidity()) {
+		t.Errorf("hermetic humidity %g, want %g", hermetic.Humidity(), 0.1*ceramic.Humidity())
+	}
+
+	// Errors
+	errs := []struct {
+		comp *Component
+		want string
+	}{
+		{&Component{Class: "U", Tags: []string{"hybrid"}, Package: "SOT23-6", Tmax: 125}, "without dies"},
+		{&Component{Class: "U", Tags: []string{"hybrid"}, Package: "SOT23-6", Tmax: 125, Dies: "cpu"}, "unknown die category"},
+		{&Component{Class: "U", Tags: []string{"hybrid"}, Package: "XYZ", Tmax: 125, Dies: "sram"}, "Missing data for lpkg"},
+	}
+
+	for _, tt := range errs {
+		if _, err := HybridEval(tt.comp, m); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.comp.Dies, err, tt.want)
+		}
+	}
+}
@@ -39,9 +39,29 @@ var css []cs = []cs{
 
 	{"C", "alu", 7, 7, 1},
 	{"C", "tant", 8, 7, 1},
+	// Film: safety classes, then dielectrics, then plain film
+	{"C", "x1", 8, 6, 1},
+	{"C", "x2", 8, 6, 1},
+	{"C", "y1", 8, 6, 1},
+	{"C", "y2", 8, 6, 1},
+	{"C", "pp", 7, 6, 1},
+	{"C", "pet", 7, 6, 1},
+	{"C", "pps", 7, 6, 1},
+	{"C", "mkp", 7, 6, 1},
+	{"C", "mkt", 7, 6, 1},
 	{"C", "film", 7, 6, 1},
+	{"C", "supercap", 7, 7, 2},
+	{"C", "edlc", 7, 7, 2},
 	{"C", "elco", 7, 7, 1},
 
+	// Thermistors and shunts before the technologies (a thick film NTC is
+	// an NTC)
+	{"R", "ntc", 4, 5, 4},
+	{"R", "ptc", 4, 5, 4},
+	{"R", "kty", 4, 5, 4},
+	{"R", "shunt", 2, 4, 2},
+	{"R", "sense", 2, 4, 2},
+
 	{"R", "melf", 4, 2, 4},
 	{"R", "fuse", 6, 6, 4},
 	{"R", "thick power", 2, 4, 1},
@@ -55,6 +75,8 @@ var css []cs = []cs{
 	{"R", "potmeter", 1, 5, 2},
 	{"R", "variable", 1, 5, 2},
 
+	{"F", "", 6, 6, 4}, // As R fuse
+
 	{"L", "trafo power", 6, 7, 4},
 	{"L", "power", 7, 6, 3},
 	{"L", "trafo", 6, 5, 3},
@@ -64,6 +86,20 @@ var css []cs = []cs{
 	{"X", "", 2, 10, 5},
 	{"RL", "", 7, 10, 2},
 	{"SW", "", 7, 10, 1},
+	{"RV", "", 8, 5, 3},
+	{"GDT", "", 8, 6, 3},
+	{"BT", "", 6, 8, 3},
+	{"FAN", "", 6, 9, 3},
+	{"RF", "gan", 8, 3, 4},
+	{"RF", "gaas", 9, 3, 5},
+	{"RF", "saw", 6, 8, 2},
+	{"RF", "baw", 6, 8, 2},
+	{"RF", "circulator", 5, 7, 2},
+	{"RF", "isolator", 5, 7, 2},
+	{"RF", "connector", 1, 10, 3},
+	{"RF", "coax", 1, 10, 3},
+	{"RF", "attenuator", 5, 5, 4},
+	{"RF", "", 8, 3, 4},
 	{"PCB", "", 4, 10, 8},
 	{"J", "", 1, 10, 3},
 }
@@ -1,37 +1,38 @@
 package fides
 
-import "math"
-
 // InductorFIT
 func InductorFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(InductorEval(comp, mission))
+}
+
+func InductorEval(comp *Component, mission *Mission) (*Result, error) {
 
 	fit, ea, lth, ltc, lm, tdelta, _ := lbase_inductor(comp.Tags)
-	var factor float64
+	r := newResult(fit)
 
 	for _, ph := range mission.Phases {
 
-		pi := 0.0
-		if ph.On {
-			pi = lth * Arrhenius25(ea, ph.Tamb+tdelta)
-		}
-
-		pi += ltc*PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax) +
-			lm*PiMech(ph.Grms)
-
 		// Proportion of time in this phase
-		pi *= ph.Duration / 8760.0
+		w := ph.Duration / 8760.0
+
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+		if mission.IsOn(comp, ph) {
+			pi.Thermal = w * lth * Arrhenius25(ea, mission.Tamb(comp, ph)+tdelta)
+		}
+		pi.TCycling = w * ltc * PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax)
+		pi.Mechanical = w * lm * PiMech(ph.Grms)
 
 		// Stress factors and sensibility
 		ifactor, err := PiInduced(comp, ph)
 		if err != nil {
-			return math.NaN(), err
+			return nil, err
 		}
-		pi *= ifactor
+		pi.Induced = ifactor
 
-		factor += pi
+		r.add(pi)
 	}
 
-	return fit * factor * PiPM() * PiProcess(), nil
+	return r, nil
 }
 
 // Returns l0, ea, lth, ltc, lmech, tdelta, Cs
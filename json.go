@@ -0,0 +1,255 @@
+package fides
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"strconv"
+)
+
+// jsonFloat is a float64 that is encoded as null when NaN (not allowed in JSON)
+type jsonFloat float64
+
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+		return []byte("null"), nil
+	}
+	return strconv.AppendFloat(nil, float64(f), 'g', -1, 64), nil
+}
+
+func (f *jsonFloat) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*f = jsonFloat(math.NaN())
+		return nil
+	}
+	v, err := strconv.ParseFloat(string(b), 64)
+	*f = jsonFloat(v)
+	return err
+}
+
+// JSON encoding of Component. The field names are those of the CSV files.
+type jsonComponent struct {
+	Name        string    `json:"name"`
+	Value       jsonFloat `json:"value,omitempty"`
+	Tolerance   jsonFloat `json:"tolerance,omitempty"`
+	Type        string    `json:"type,omitempty"`
+	Description string    `json:"description,omitempty"`
+
+	Class string   `json:"class"`
+	Tags  []string `json:"tags,omitempty"`
+	Block string   `json:"block,omitempty"`
+	Zone  string   `json:"zone,omitempty"`
+
+	Package string    `json:"package,omitempty"`
+	N       int       `json:"ndevices,omitempty"`
+	Np      int       `json:"npins,omitempty"`
+	Rtha    jsonFloat `json:"rtha,omitempty"`
+	Rca     jsonFloat `json:"rca,omitempty"`
+
+	Vp    jsonFloat `json:"vp,omitempty"`
+	V     jsonFloat `json:"v,omitempty"`
+	P     jsonFloat `json:"p,omitempty"`
+	I     jsonFloat `json:"i,omitempty"`
+	T     jsonFloat `json:"t,omitempty"`
+	Vpmax jsonFloat `json:"vpmax,omitempty"`
+	Vmax  jsonFloat `json:"vmax,omitempty"`
+	Pmax  jsonFloat `json:"pmax,omitempty"`
+	Imax  jsonFloat `json:"imax,omitempty"`
+	Tmax  jsonFloat `json:"tmax,omitempty"`
+
+	TC jsonFloat `json:"tc,omitempty"`
+
+	Ops    jsonFloat `json:"ops,omitempty"`
+	Duty   jsonFloat `json:"duty,omitempty"`
+	Cycles jsonFloat `json:"cycles,omitempty"`
+
+	Ripple jsonFloat `json:"ripple,omitempty"`
+	Esr    jsonFloat `json:"esr,omitempty"`
+	Life   jsonFloat `json:"life,omitempty"`
+	Tref   jsonFloat `json:"tref,omitempty"`
+	Speed  jsonFloat `json:"speed,omitempty"`
+
+	Node     jsonFloat `json:"node,omitempty"`
+	Vcore    jsonFloat `json:"vcore,omitempty"`
+	Activity jsonFloat `json:"activity,omitempty"`
+
+	Prf    jsonFloat `json:"prf,omitempty"`
+	Prfmax jsonFloat `json:"prfmax,omitempty"`
+	Freq   jsonFloat `json:"freq,omitempty"`
+
+	Dies string `json:"dies,omitempty"`
+
+	Ip jsonFloat `json:"ip,omitempty"`
+	Tp jsonFloat `json:"tp,omitempty"`
+
+	Surge      jsonFloat `json:"surge,omitempty"`
+	Surges     jsonFloat `json:"surges,omitempty"`
+	Emax       jsonFloat `json:"emax,omitempty"`
+	Vwithstand jsonFloat `json:"vwithstand,omitempty"`
+
+	Loads map[string]*jsonLoad `json:"loads,omitempty"`
+
+	FIT jsonFloat `json:"fit,omitempty"`
+}
+
+type jsonLoad struct {
+	V jsonFloat `json:"v"`
+	P jsonFloat `json:"p"`
+	I jsonFloat `json:"i"`
+	T jsonFloat `json:"t"`
+
+	Ops   jsonFloat `json:"ops"`
+	Duty  jsonFloat `json:"duty"`
+	Speed jsonFloat `json:"speed"`
+
+	Surge  jsonFloat `json:"surge"`
+	Surges jsonFloat `json:"surges"`
+	Prf    jsonFloat `json:"prf"`
+}
+
+func (c *Component) MarshalJSON() ([]byte, error) {
+
+	j := &jsonComponent{
+		Name: c.Name, Value: jsonFloat(c.Value), Tolerance: jsonFloat(c.Tolerance), Type: c.Type, Description: c.Description,
+		Class: c.Class, Tags: c.Tags, Block: c.Block, Zone: c.Zone,
+		Package: c.Package, N: c.N, Np: c.Np, Rtha: jsonFloat(c.Rtha), Rca: jsonFloat(c.Rca),
+		Vp: jsonFloat(c.Vp), V: jsonFloat(c.V), P: jsonFloat(c.P), I: jsonFloat(c.I), T: jsonFloat(c.T),
+		Vpmax: jsonFloat(c.Vpmax), Vmax: jsonFloat(c.Vmax), Pmax: jsonFloat(c.Pmax), Imax: jsonFloat(c.Imax), Tmax: jsonFloat(c.Tmax),
+		TC: jsonFloat(c.TC), Ops: jsonFloat(c.Ops), Duty: jsonFloat(c.Duty), Cycles: jsonFloat(c.Cycles),
+		Ripple: jsonFloat(c.Ripple), Esr: jsonFloat(c.Esr), Life: jsonFloat(c.Life), Tref: jsonFloat(c.Tref), Speed: jsonFloat(c.Speed),
+		Node: jsonFloat(c.Node), Vcore: jsonFloat(c.Vcore), Activity: jsonFloat(c.Activity),
+		Prf: jsonFloat(c.Prf), Prfmax: jsonFloat(c.Prfmax), Freq: jsonFloat(c.Freq),
+		Dies: c.Dies, Ip: jsonFloat(c.Ip), Tp: jsonFloat(c.Tp),
+		Surge: jsonFloat(c.Surge), Surges: jsonFloat(c.Surges), Emax: jsonFloat(c.Emax), Vwithstand: jsonFloat(c.Vwithstand), FIT: jsonFloat(c.FIT),
+	}
+
+	if len(c.Loads) > 0 {
+		j.Loads = make(map[string]*jsonLoad)
+		for ph, ld := range c.Loads {
+			j.Loads[ph] = &jsonLoad{V: jsonFloat(ld.V), P: jsonFloat(ld.P), I: jsonFloat(ld.I), T: jsonFloat(ld.T),
+				Ops: jsonFloat(ld.Ops), Duty: jsonFloat(ld.Duty), Speed: jsonFloat(ld.Speed), Surge: jsonFloat(ld.Surge), Surges: jsonFloat(ld.Surges),
+				Prf: jsonFloat(ld.Prf)}
+		}
+	}
+
+	return json.Marshal(j)
+}
+
+func (c *Component) UnmarshalJSON(b []byte) error {
+
+	j := &jsonComponent{}
+	if err := json.Unmarshal(b, j); err != nil {
+		return err
+	}
+
+	*c = Component{
+		Name: j.Name, Value: float64(j.Value), Tolerance: float64(j.Tolerance), Type: j.Type, Description: j.Description,
+		Class: j.Class, Tags: j.Tags, Block: j.Block, Zone: j.Zone,
+		Package: j.Package, N: j.N, Np: j.Np, Rtha: float64(j.Rtha), Rca: float64(j.Rca),
+		Vp: float64(j.Vp), V: float64(j.V), P: float64(j.P), I: float64(j.I), T: float64(j.T),
+		Vpmax: float64(j.Vpmax), Vmax: float64(j.Vmax), Pmax: float64(j.Pmax), Imax: float64(j.Imax), Tmax: float64(j.Tmax),
+		TC: float64(j.TC), Ops: float64(j.Ops), Duty: float64(j.Duty), Cycles: float64(j.Cycles),
+		Ripple: float64(j.Ripple), Esr: float64(j.Esr), Life: float64(j.Life), Tref: float64(j.Tref), Speed: float64(j.Speed),
+		Node: float64(j.Node), Vcore: float64(j.Vcore), Activity: float64(j.Activity),
+		Prf: float64(j.Prf), Prfmax: float64(j.Prfmax), Freq: float64(j.Freq),
+		Dies: j.Dies, Ip: float64(j.Ip), Tp: float64(j.Tp),
+		Surge: float64(j.Surge), Surges: float64(j.Surges), Emax: float64(j.Emax), Vwithstand: float64(j.Vwithstand), FIT: float64(j.FIT),
+	}
+
+	if len(j.Loads) > 0 {
+		c.Loads = make(map[string]*Load)
+		for ph, ld := range j.Loads {
+			c.Loads[ph] = &Load{V: float64(ld.V), P: float64(ld.P), I: float64(ld.I), T: float64(ld.T),
+				Ops: float64(ld.Ops), Duty: float64(ld.Duty), Speed: float64(ld.Speed), Surge: float64(ld.Surge), Surges: float64(ld.Surges),
+				Prf: float64(ld.Prf)}
+		}
+	}
+
+	return nil
+}
+
+func (bom *Bom) ToJson() (string, error) {
+	b, err := json.MarshalIndent(bom, "", "  ")
+	return string(b), err
+}
+
+func (bom *Bom) FromJson(file string) error {
+	return readJson(file, bom)
+}
+
+func (m *Mission) ToJson() (string, error) {
+	b, err := json.MarshalIndent(m, "", "  ")
+	return string(b), err
+}
+
+// FromJson reads a mission from a JSON file. The total duration is the sum of
+// the durations of the phases, as in FromCsv.
+func (m *Mission) FromJson(file string) error {
+
+	if err := readJson(file, m); err != nil {
+		return err
+	}
+
+	m.Ttotal = 0
+	for _, ph := range m.Phases {
+		m.Ttotal += ph.Duration
+	}
+	return nil
+}
+
+// Evaluation is the outcome of the FIT calculation of one component
+type Evaluation struct {
+	Name   string  `json:"name"`
+	Result *Result `json:"result,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// Report holds a complete analysis: the BOM, the mission and the results
+type Report struct {
+	Bom     *Bom          `json:"bom"`
+	Mission *Mission      `json:"mission"`
+	Results []*Evaluation `json:"results"`
+	FIT     float64       `json:"fit"` // Sum of the FIT of all components without errors
+}
+
+// NewReport evaluates all components of the BOM for the given mission. The
+// FIT of each component is also stored in Component.FIT.
+func NewReport(bom *Bom, mission *Mission) *Report {
+
+	rep := &Report{Bom: bom, Mission: mission}
+
+	for _, c := range bom.Components {
+
+		r, err := Evaluate(c, mission)
+		e := &Evaluation{Name: c.Name, Result: r}
+
+		if err != nil {
+			c.FIT = math.NaN()
+			e.Error = err.Error()
+		} else {
+			c.FIT = r.FIT
+			rep.FIT += r.FIT
+		}
+		rep.Results = append(rep.Results, e)
+	}
+
+	return rep
+}
+
+func (rep *Report) ToJson() (string, error) {
+	b, err := json.MarshalIndent(rep, "", "  ")
+	return string(b), err
+}
+
+func (rep *Report) FromJson(file string) error {
+	return readJson(file, rep)
+}
+
+func readJson(file string, v interface{}) error {
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,113 @@
+package fides
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestComponentJson(t *testing.T) {
+
+	ld := NewLoad()
+	ld.V = 12
+	c := &Component{Name: "C1", Class: "C", Tags: []string{"x7r"}, Package: "0805", Value: 100e-9,
+		Tmax: 125, Vmax: 50, V: 5, FIT: math.NaN(), Loads: map[string]*Load{"run": ld}}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`"name":"C1"`, `"vmax":50`, `"value":1e-07`, `"tags":["x7r"]`, `"v":12`, `"p":null`, `"fit":null`} {
+		if !strings.Contains(string(b), s) {
+			t.Errorf("%s not in %s", s, b)
+		}
+	}
+
+	var d Component
+	if err := json.Unmarshal(b, &d); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"value", d.Value, c.Value},
+		{"tmax", d.Tmax, c.Tmax},
+		{"vmax", d.Vmax, c.Vmax},
+		{"v", d.V, c.V},
+		{"load v", d.Loads["run"].V, 12},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %g, want %g", tt.name, tt.got, tt.want)
+		}
+	}
+	if d.Name != "C1" || d.Class != "C" || d.Package != "0805" || len(d.Tags) != 1 {
+		t.Errorf("decoded %+v", d)
+	}
+	if !math.IsNaN(d.Loads["run"].P) || !math.IsNaN(d.FIT) {
+		t.Errorf("load p = %g and FIT = %g, want NaN", d.Loads["run"].P, d.FIT)
+	}
+}
+
+func TestMissionFromJson(t *testing.T) {
+
+	m := testMission(40)
+	m.Phases = append(m.Phases, &Phase{Name: "storage", Duration: 1000, Tamb: 20})
+	s, err := m.ToJson()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The duration in the file is not used
+	s = strings.Replace(s, `"duration": 8760,`, `"duration": 1,`, 1)
+
+	var d Mission
+	if err := d.FromJson(testFile(t, "mission.json", s)); err != nil {
+		t.Fatal(err)
+	}
+	if d.Ttotal != 9760 {
+		t.Errorf("Ttotal = %g, want 9760", d.Ttotal)
+	}
+	if len(d.Phases) != 2 || d.Phases[0].Tamb != 40 || d.Phases[0].AppFactor != 4.8 || d.Phases[1].On {
+		t.Errorf("phases %+v", d.Phases)
+	}
+
+	if err := d.FromJson(testFile(t, "mission.json", "{")); err == nil {
+		t.Error("no error for invalid JSON")
+	}
+}
+
+func TestReportJson(t *testing.T) {
+
+	bom := &Bom{Components: []*Component{
+		{Name: "R1", Class: "R", Tags: []string{"thick"}, Package: "0805", Tmax: 155, Pmax: 0.125, P: 0.05},
+		{Name: "R2", Class: "R", Package: "0805", Pmax: 0.125, P: 0.05},
+	}}
+	rep := NewReport(bom, testMission(40))
+
+	if rep.Results[0].Error != "" || rep.Results[1].Error == "" {
+		t.Fatalf("errors %q, %q", rep.Results[0].Error, rep.Results[1].Error)
+	}
+	if rep.FIT != bom.Components[0].FIT || !math.IsNaN(bom.Components[1].FIT) {
+		t.Errorf("FIT %g, components %g and %g", rep.FIT, bom.Components[0].FIT, bom.Components[1].FIT)
+	}
+
+	s, err := rep.ToJson()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var d Report
+	if err := d.FromJson(testFile(t, "report.json", s)); err != nil {
+		t.Fatal(err)
+	}
+	if d.FIT != rep.FIT || len(d.Results) != 2 || d.Results[1].Error != rep.Results[1].Error {
+		t.Errorf("decoded report %+v", d)
+	}
+	if !near(d.Results[0].Result.FIT, rep.Results[0].Result.FIT) {
+		t.Errorf("FIT of R1 %g, want %g", d.Results[0].Result.FIT, rep.Results[0].Result.FIT)
+	}
+}
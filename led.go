@@ -0,0 +1,160 @@
+package fides
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// LedFIT returns the FIT of a LED (class D, tag led)
+func LedFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(LedEval(comp, mission))
+}
+
+// LedEval returns the detailed FIT of a LED. As for other semiconductors, the
+// base lambdas are in FIT and the Base of the result is 1.
+//
+// The junction temperature is calculated as for other semiconductors
+// (Tjunction), with the power from the forward current (I) and voltage (V, or
+// the typical value of the technology if not given).
+func LedEval(comp *Component, mission *Mission) (*Result, error) {
+
+	if comp.I == 0 || math.IsNaN(comp.I) {
+		return nil, errors.New("forward current I not set")
+	}
+
+	tech := ledTech(comp.Tags)
+	high := ledHighPower(comp)
+	lth, ea, vf := lbase_led(tech, high)
+
+	// Die and wire bonding, as for optocouplers
+	ltc_chip := 0.021
+	lm_chip := 0.011
+
+	p := NewPackage(comp.Package)
+	lrh, ltc, lts, lm := p.FitBase()
+	if lrh < 0 || math.IsNaN(lrh) {
+		// LED packages (0603, PLCC, 5 mm) are mostly not in the database:
+		// use the values of small signal plastic packages.
+		if IsSmd(comp) {
+			lrh, ltc, lts, lm = 0.0055, 0.00057, 0.00285, 0.000057
+		} else {
+			lrh, ltc, lts, lm = 0.031, 0.001, 0.0055, 0.00011
+		}
+	}
+
+	r := newResult(1)
+
+	for _, ph := range mission.Phases {
+
+		// Working conditions in this phase
+		c := comp.InPhase(ph)
+		on := mission.IsOn(comp, ph)
+		tamb := mission.Tamb(comp, ph)
+
+		if comp.Imax > 0 && c.I > comp.Imax {
+			s := fmt.Sprintf("Forward current (%f A) exceeds its Imax (%f A)", c.I, comp.Imax)
+			return nil, errors.New(s)
+		}
+
+		c.P = ledPower(c, vf)
+		if c.Rca <= 0 && c.Rtha <= 0 {
+			if rth := p.Rtha(0); rth <= 0 || math.IsNaN(rth) {
+				c.Rtha = ledRth(high)
+			}
+		}
+
+		tj, err := phaseTj(c, p, tamb, on)
+		if err != nil {
+			return nil, err
+		}
+
+		// Proportion of time in this phase
+		w := ph.Duration / mission.Ttotal
+
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+		pi.Thermal = w * lth * PiThermal(ea, tj, on)
+		pi.TCycling = w * (ltc*PiTCCase(ph.NCycles, ph.Duration, ph.Tdelta, ph.Tmax) +
+			(lts+ltc_chip)*PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax))
+		pi.Humidity = w * lrh * PiRH2(0.9, ph.RH, tamb, on)
+		pi.Mechanical = w * (lm + lm_chip) * PiMech(ph.Grms)
+
+		// Stress factors and sensibility
+		ifactor, err := PiInduced(comp, ph)
+		if err != nil {
+			return nil, err
+		}
+		pi.Induced = ifactor
+
+		r.add(pi)
+	}
+
+	return r, nil
+}
+
+// ledTech returns the LED technology from the tags: ingan (blue, green,
+// white), algainp (red, orange, amber, yellow) or gaas (infrared).
+// Default is ingan.
+func ledTech(tags []string) string {
+
+	for _, tag := range tags {
+		switch tag {
+		case "gaas", "ir", "infrared":
+			return "gaas"
+		case "algainp", "red", "orange", "amber", "yellow":
+			return "algainp"
+		}
+	}
+	return "ingan"
+}
+
+// High power (illumination) LEDs: tag power or rated power of 0.5 W or more
+func ledHighPower(c *Component) bool {
+	return contains(c.Tags, "power") || c.Pmax >= 0.5
+}
+
+// Returns lth, Ea and the typical forward voltage
+func lbase_led(tech string, high bool) (float64, float64, float64) {
+
+	switch tech {
+	case "gaas":
+		if high {
+			return 0.25, 0.4, 1.5
+		}
+		return 0.04, 0.4, 1.3
+	case "algainp":
+		if high {
+			return 0.3, 0.5, 2.2
+		}
+		return 0.05, 0.5, 2.0
+	}
+
+	// ingan
+	if high {
+		return 0.4, 0.6, 3.2
+	}
+	return 0.08, 0.6, 3.0
+}
+
+// ledPower returns the power dissipated by the LED: P if given, else V*I
+// (with the typical forward voltage if V is not given).
+func ledPower(c *Component, vf float64) float64 {
+
+	if c.P > 0 {
+		return c.P
+	}
+	if c.V > 0 {
+		vf = c.V
+	}
+	return vf * c.I
+}
+
+// ledRth returns a typical junction to ambient thermal resistance for the
+// power class, used when neither the BOM nor the package give one.
+func ledRth(high bool) float64 {
+
+	if high {
+		return 60
+	}
+	return 500
+}
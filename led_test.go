@@ -0,0 +1,80 @@
+package fides
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLedTech(t *testing.T) {
+
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{[]string{"led"}, "ingan"},
+		{[]string{"led", "white"}, "ingan"},
+		{[]string{"led", "red"}, "algainp"},
+		{[]string{"led", "amber"}, "algainp"},
+		{[]string{"led", "ir"}, "gaas"},
+	}
+
+	for _, tt := range tests {
+		if got := ledTech(tt.tags); got != tt.want {
+			t.Errorf("ledTech(%v) = %s, want %s", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestLedPower(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		p, v, i  float64
+		vf, want float64
+	}{
+		{"p", 0.1, 3, 0.02, 3, 0.1},
+		{"v", 0, 2.8, 0.02, 3, 0.056},
+		{"typical vf", 0, 0, 0.02, 3, 0.06},
+	}
+
+	for _, tt := range tests {
+		c := &Component{P: tt.p, V: tt.v, I: tt.i}
+		if got := ledPower(c, tt.vf); !near(got, tt.want) {
+			t.Errorf("%s: ledPower = %g, want %g", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLedEval(t *testing.T) {
+
+	m := testMission(40)
+
+	fit := func(i float64) float64 {
+		c := &Component{Class: "D", Tags: []string{"led"}, Package: "0603", Tmax: 125, I: i}
+		r, err := LedEval(c, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r.FIT
+	}
+	if fit(0.02) <= fit(0.005) {
+		t.Error("the FIT does not increase with the forward current")
+	}
+
+	tests := []struct {
+		name string
+		comp *Component
+		err  string
+	}{
+		{"no current", &Component{Class: "D", Tags: []string{"led"}, Package: "0603", Tmax: 125}, "forward current"},
+		{"above imax", &Component{Class: "D", Tags: []string{"led"}, Package: "0603", Tmax: 125, I: 0.05, Imax: 0.03}, "Imax"},
+		{"above tmax", &Component{Class: "D", Tags: []string{"led"}, Package: "0603", Tmax: 85, I: 0.03}, "Tmax"},
+	}
+
+	for _, tt := range tests {
+		_, err := LedEval(tt.comp, m)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
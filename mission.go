@@ -1,42 +1,66 @@
 package fides
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
+	"sort"
+	"strings"
 )
 
 type Phase struct {
-	Name          string
-	Duration      float64
-	NCycles       int
-	CycleDuration float64
-	On            bool
-	Tamb          float64
-	Tdelta        float64
-	Tmax          float64
-	RH            float64
-	Grms          float64
+	Name          string  `json:"phase"`
+	Duration      float64 `json:"duration"`
+	NCycles       int     `json:"ncycles"`
+	CycleDuration float64 `json:"tcycle"`
+	On            bool    `json:"on"`
+	Tamb          float64 `json:"tamb"`
+	Tdelta        float64 `json:"tdelta"`
+	Tmax          float64 `json:"tmax"`
+	RH            float64 `json:"rh"`
+	Grms          float64 `json:"grms"`
 
 	// 1 = weak,low; 2 = high,strong
-	SalinePollution float64
+	SalinePollution float64 `json:"saline_pollution"`
 
 	// 1 = weak,low; 1.5 = moderate; 2 = strong,high
-	AmbientPollution float64
+	AmbientPollution float64 `json:"env_pollution"`
 
 	// 1 = weak,low; 2 = moderate, 4 = strong,high
-	ZonePollution float64
+	ZonePollution float64 `json:"app_pollution"`
 
 	// Ingress protection (true = hermetic, sealed)
-	IP   bool
-	Tags string
+	IP   bool   `json:"ip"`
+	Tags string `json:"tags,omitempty"`
+
+	// Board bending (flexing by assembly, handling, screws or connectors):
+	// 0 = none, 1 = low, 2 = moderate, 4 = high
+	Bending float64 `json:"bending,omitempty"`
 
 	// Application factor
-	AppFactor float64
+	AppFactor float64 `json:"pi_app"`
+
+	// State of the power domains in this phase. Domains not listed follow On.
+	Domains map[string]bool `json:"domains,omitempty"`
 }
 
 type Mission struct {
-	Ttotal float64 // Total mission duration
-	Phases []*Phase
+	Ttotal float64  `json:"duration"` // Total mission duration
+	Phases []*Phase `json:"phases"`
+
+	// Power domain of each block (optional)
+	Blocks map[string]string `json:"blocks,omitempty"`
+
+	// Thermal zones (optional)
+	Zones map[string]*Zone `json:"zones,omitempty"`
+}
+
+// Zone is a region of the board that is hotter than the ambient, for example
+// around a power stage. The rise applies only to phases where the component
+// (or, in ZonesToMD, a block of the zone) is powered.
+type Zone struct {
+	Name   string   `json:"zone"`
+	Rise   float64  `json:"rise"`   // Temperature over the phase ambient
+	Blocks []string `json:"blocks"` // Blocks in this zone
 }
 
 func NewMission() *Mission {
@@ -50,32 +74,160 @@ func (mission *Mission) FromCsv(file string) error {
 		return err
 	}
 
+	var errs []error
+
 	for i := 0; i < len(m); i++ {
 		ph := &Phase{}
 		p := m[i]
 
 		ph.Name = p["phase"]
-		ph.Duration, _ = strconv.ParseFloat(p["duration"], 64)
+		ph.Duration = parseField(&errs, ph.Name, "duration", p["duration"], "h", 0)
 		ph.On = (p["on"] == "on" || p["on"] == "true")
-		ph.Tamb, _ = strconv.ParseFloat(p["tamb"], 64)
-		ph.Tdelta, _ = strconv.ParseFloat(p["tdelta"], 64)
-		ph.NCycles, _ = strconv.Atoi(p["ncycles"])
-		ph.CycleDuration, _ = strconv.ParseFloat(p["tcycle"], 64)
-		ph.Tmax, _ = strconv.ParseFloat(p["tmax"], 64)
-		ph.RH, _ = strconv.ParseFloat(p["rh"], 64)
-		ph.Grms, _ = strconv.ParseFloat(p["grms"], 64)
+		ph.Tamb = parseField(&errs, ph.Name, "tamb", p["tamb"], "ºC", 0)
+		ph.Tdelta = parseField(&errs, ph.Name, "tdelta", p["tdelta"], "ºC", 0)
+		ph.NCycles = int(parseField(&errs, ph.Name, "ncycles", p["ncycles"], "", 0))
+		ph.CycleDuration = parseField(&errs, ph.Name, "tcycle", p["tcycle"], "h", 0)
+		ph.Tmax = parseField(&errs, ph.Name, "tmax", p["tmax"], "ºC", 0)
+		ph.RH = parseField(&errs, ph.Name, "rh", p["rh"], "%", 0)
+		ph.Grms = parseField(&errs, ph.Name, "grms", p["grms"], "g", 0)
 		ph.SalinePollution = level(2, p["saline_pollution"])
 		ph.AmbientPollution = level(2, p["env_pollution"])
 		ph.ZonePollution = level(4, p["app_pollution"])
 		ph.IP = (p["ip"] == "sealed" || p["ip"] == "hermetic")
-		ph.AppFactor, _ = strconv.ParseFloat(p["pi_app"], 64)
+		ph.AppFactor = parseField(&errs, ph.Name, "pi_app", p["pi_app"], "", 0)
+		if s := p["bending"]; s != "" && s != "none" {
+			ph.Bending = level(4, s)
+		}
 
 		mission.Phases = append(mission.Phases, ph)
 		mission.Ttotal += ph.Duration
 	}
+	return joinErrors(errs)
+}
+
+// DomainsFromCsv reads the power domains from a CSV file with the fields
+// domain, blocks (space separated list of the blocks in the domain) and on
+// (space separated list of the phases in which the domain is powered).
+// It should be called after the phases are defined.
+func (mission *Mission) DomainsFromCsv(file string) error {
+
+	m, err := csvRead(file)
+	if err != nil {
+		return err
+	}
+
+	if mission.Blocks == nil {
+		mission.Blocks = make(map[string]string)
+	}
+
+	for _, r := range m {
+
+		domain := r["domain"]
+		if domain == "" {
+			return errors.New("power domain without name")
+		}
+
+		for _, block := range strings.Fields(r["blocks"]) {
+			mission.Blocks[block] = domain
+		}
+
+		on := strings.Fields(r["on"])
+		for _, ph := range mission.Phases {
+			if ph.Domains == nil {
+				ph.Domains = make(map[string]bool)
+			}
+			ph.Domains[domain] = contains(on, strings.ToLower(ph.Name))
+		}
+	}
+
+	return nil
+}
+
+// IsOn returns true if the component is powered in the given phase, taking
+// into account the power domain of its block.
+func (mission *Mission) IsOn(comp *Component, ph *Phase) bool {
+
+	domain, ok := mission.Blocks[strings.ToLower(comp.Block)]
+	if !ok {
+		return ph.On
+	}
+
+	on, ok := ph.Domains[domain]
+	if !ok {
+		return ph.On
+	}
+	return on
+}
+
+// ZonesFromCsv reads the thermal zones from a CSV file with the fields
+// zone, rise (temperature over the ambient) and blocks (space separated list
+// of the blocks in this zone). Components can also be assigned to a zone
+// directly in the BOM.
+func (mission *Mission) ZonesFromCsv(file string) error {
+
+	m, err := csvRead(file)
+	if err != nil {
+		return err
+	}
+
+	if mission.Zones == nil {
+		mission.Zones = make(map[string]*Zone)
+	}
+
+	for _, r := range m {
+
+		z := &Zone{Name: r["zone"]}
+		if z.Name == "" {
+			return errors.New("thermal zone without name")
+		}
+
+		z.Rise, err = ParseUnit(r["rise"], "ºC")
+		if err != nil {
+			return errors.New("thermal zone " + z.Name + ": rise: " + err.Error() + " [" + r["rise"] + "]")
+		}
+		z.Blocks = strings.Fields(r["blocks"])
+
+		mission.Zones[z.Name] = z
+	}
+
+	return nil
+}
+
+// Zone returns the thermal zone of the component, or nil if none. A zone
+// given for the component has priority over the zone of its block. Zone names
+// are not case sensitive.
+func (mission *Mission) Zone(comp *Component) *Zone {
+
+	if comp.Zone != "" {
+		for name, z := range mission.Zones {
+			if strings.EqualFold(name, comp.Zone) {
+				return z
+			}
+		}
+		return nil
+	}
+
+	block := strings.ToLower(comp.Block)
+	for _, z := range mission.Zones {
+		if contains(z.Blocks, block) {
+			return z
+		}
+	}
 	return nil
 }
 
+// Tamb returns the local ambient temperature of the component in a phase:
+// the ambient of the phase plus the rise of its thermal zone if the component
+// is powered (IsOn).
+func (mission *Mission) Tamb(comp *Component, ph *Phase) float64 {
+
+	z := mission.Zone(comp)
+	if z == nil || !mission.IsOn(comp, ph) {
+		return ph.Tamb
+	}
+	return ph.Tamb + z.Rise
+}
+
 func level(max float64, s string) float64 {
 
 	switch s {
@@ -90,7 +242,7 @@ func level(max float64, s string) float64 {
 
 func (m *Mission) ToCsv() string {
 
-	s := "phase, duration, on, tamb, tdelta, ncycles, tcycle, rh, grms, tmax, saline, env, app, ip, factor\n"
+	s := "phase, duration, on, tamb, tdelta, ncycles, tcycle, rh, grms, tmax, saline, env, app, ip, factor, bending\n"
 
 	for _, ph := range m.Phases {
 		s += fmt.Sprintf("%s, ", ph.Name)
@@ -106,15 +258,67 @@ func (m *Mission) ToCsv() string {
 		s += fmt.Sprintf("%.0f, ", ph.AmbientPollution)
 		s += fmt.Sprintf("%.0f, ", ph.ZonePollution)
 		s += fmt.Sprintf("%t, ", ph.IP)
-		s += fmt.Sprintf("%.1f\n", ph.AppFactor)
+		s += fmt.Sprintf("%.1f, ", ph.AppFactor)
+		s += fmt.Sprintf("%.0f\n", ph.Bending)
+	}
+	return s
+}
+
+// ZonesToMD returns a table with the temperature of each thermal zone per phase
+func (m *Mission) ZonesToMD() string {
+
+	if len(m.Zones) == 0 {
+		return ""
+	}
+
+	var names []string
+	for name := range m.Zones {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	s := "| Zone | Rise | Blocks |"
+	l := "|---|---|---|"
+	for _, ph := range m.Phases {
+		s += " " + ph.Name + " |"
+		l += "---|"
+	}
+	s += "\n" + l + "\n"
+
+	for _, name := range names {
+		z := m.Zones[name]
+		s += fmt.Sprintf("| %s | %.1f | %s |", z.Name, z.Rise, strings.Join(z.Blocks, " "))
+		for _, ph := range m.Phases {
+			t := ph.Tamb
+			if m.zoneOn(z, ph) {
+				t += z.Rise
+			}
+			s += fmt.Sprintf(" %.1f |", t)
+		}
+		s += "\n"
 	}
 	return s
 }
 
+// zoneOn returns true if any block of the zone is powered in the phase (the
+// phase state if the zone has no blocks)
+func (m *Mission) zoneOn(z *Zone, ph *Phase) bool {
+
+	if len(z.Blocks) == 0 {
+		return ph.On
+	}
+	for _, b := range z.Blocks {
+		if m.IsOn(&Component{Block: b}, ph) {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Mission) ToMD() string {
 
-	s := "| Phase | Duration | On | Tamb | Tdelta | Ncycles | Tcycle | RH | Grms | Tmax | Saline pol | Env pol | Appl pol | IP | Factor |\n"
-	s += "|---|---|---|---|---|---|---|---|---|---|---|---|---|---|---|\n"
+	s := "| Phase | Duration | On | Tamb | Tdelta | Ncycles | Tcycle | RH | Grms | Tmax | Saline pol | Env pol | Appl pol | IP | Factor | Bending |\n"
+	s += "|---|---|---|---|---|---|---|---|---|---|---|---|---|---|---|---|\n"
 
 	for _, ph := range m.Phases {
 		s += fmt.Sprintf("| %s ", ph.Name)
@@ -130,7 +334,8 @@ func (m *Mission) ToMD() string {
 		s += fmt.Sprintf("| %.0f ", ph.AmbientPollution)
 		s += fmt.Sprintf("| %.0f ", ph.ZonePollution)
 		s += fmt.Sprintf("| %t ", ph.IP)
-		s += fmt.Sprintf("| %.1f |\n", ph.AppFactor)
+		s += fmt.Sprintf("| %.1f ", ph.AppFactor)
+		s += fmt.Sprintf("| %.0f |\n", ph.Bending)
 	}
 	return s
 }
@@ -0,0 +1,131 @@
+package fides
+
+import "testing"
+
+func TestIsOn(t *testing.T) {
+
+	m := &Mission{Blocks: map[string]string{"radio": "rf", "cpu": "core"}}
+	run := &Phase{Name: "run", On: true, Domains: map[string]bool{"rf": false}}
+	sleep := &Phase{Name: "sleep", On: false, Domains: map[string]bool{"rf": true}}
+
+	tests := []struct {
+		block string
+		ph    *Phase
+		want  bool
+	}{
+		{"", run, true},
+		{"", sleep, false},
+		{"radio", run, false},
+		{"Radio", sleep, true},
+		{"cpu", run, true},
+		{"cpu", sleep, false},
+		{"other", sleep, false},
+	}
+
+	for _, tt := range tests {
+		if got := m.IsOn(&Component{Block: tt.block}, tt.ph); got != tt.want {
+			t.Errorf("IsOn(%q, %s) = %v, want %v", tt.block, tt.ph.Name, got, tt.want)
+		}
+	}
+}
+
+func TestDomainsFromCsv(t *testing.T) {
+
+	m := &Mission{}
+	err := m.FromCsv(testFile(t, "mission.csv", "phase, duration, on, tamb\nRun, 1000, on, 40\nSleep, 7760, off, 25\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Ttotal != 8760 {
+		t.Errorf("Ttotal = %g, want 8760", m.Ttotal)
+	}
+
+	err = m.DomainsFromCsv(testFile(t, "domains.csv", "domain, blocks, on\nrf, radio antenna, run\naon, rtc, run sleep\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		block string
+		on    []bool
+	}{
+		{"radio", []bool{true, false}},
+		{"antenna", []bool{true, false}},
+		{"rtc", []bool{true, true}},
+		{"cpu", []bool{true, false}},
+	}
+
+	for _, tt := range tests {
+		for i, ph := range m.Phases {
+			if got := m.IsOn(&Component{Block: tt.block}, ph); got != tt.on[i] {
+				t.Errorf("%s in %s: on %v, want %v", tt.block, ph.Name, got, tt.on[i])
+			}
+		}
+	}
+
+	if err := m.DomainsFromCsv(testFile(t, "domains.csv", "domain, blocks, on\n, radio, run\n")); err == nil {
+		t.Error("no error for a domain without name")
+	}
+}
+
+func TestZone(t *testing.T) {
+
+	m := &Mission{
+		Blocks: map[string]string{"psu": "aux"},
+		Zones: map[string]*Zone{
+			"hot":  {Name: "hot", Rise: 30, Blocks: []string{"psu"}},
+			"warm": {Name: "warm", Rise: 10, Blocks: []string{"cpu"}},
+		},
+	}
+	on := &Phase{Name: "run", On: true, Tamb: 40}
+	auxOff := &Phase{Name: "standby", On: true, Tamb: 40, Domains: map[string]bool{"aux": false}}
+	off := &Phase{Name: "storage", On: false, Tamb: 20}
+
+	tests := []struct {
+		name string
+		comp *Component
+		zone string
+		tamb []float64
+	}{
+		{"no zone", &Component{}, "", []float64{40, 40, 20}},
+		{"block", &Component{Block: "PSU"}, "hot", []float64{70, 40, 20}},
+		{"other block", &Component{Block: "cpu"}, "warm", []float64{50, 50, 20}},
+		{"zone over block", &Component{Block: "cpu", Zone: "Hot"}, "hot", []float64{70, 70, 20}},
+		{"unknown zone", &Component{Zone: "cold"}, "", []float64{40, 40, 20}},
+	}
+
+	for _, tt := range tests {
+
+		z := m.Zone(tt.comp)
+		if (z == nil && tt.zone != "") || (z != nil && z.Name != tt.zone) {
+			t.Errorf("%s: zone %v, want %q", tt.name, z, tt.zone)
+		}
+
+		for i, ph := range []*Phase{on, auxOff, off} {
+			if got := m.Tamb(tt.comp, ph); got != tt.tamb[i] {
+				t.Errorf("%s in %s: Tamb = %g, want %g", tt.name, ph.Name, got, tt.tamb[i])
+			}
+		}
+	}
+}
+
+func TestZonesFromCsv(t *testing.T) {
+
+	m := &Mission{}
+	if err := m.ZonesFromCsv(testFile(t, "zones.csv", "zone, rise, blocks\nhot, 30C, psu charger\n")); err != nil {
+		t.Fatal(err)
+	}
+	if z := m.Zone(&Component{Block: "charger"}); z == nil || z.Rise != 30 {
+		t.Errorf("zone of charger: %v", z)
+	}
+
+	tests := []string{
+		"zone, rise, blocks\n, 30, psu\n",
+		"zone, rise, blocks\nhot, 30V, psu\n",
+	}
+	for _, data := range tests {
+		if err := m.ZonesFromCsv(testFile(t, "zones.csv", data)); err == nil {
+			t.Errorf("no error for %q", data)
+		}
+	}
+}
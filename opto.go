@@ -1,10 +1,12 @@
 package fides
 
-import "math"
-
 func OptoFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(OptoEval(comp, mission))
+}
 
-	var fit, factor float64
+// OptoEval returns the detailed FIT of optocouplers. As for other
+// semiconductors, the base lambdas are in FIT and the Base of the result is 1.
+func OptoEval(comp *Component, mission *Mission) (*Result, error) {
 
 	lth := Lchip_th(comp)
 	ltc_chip := 0.021
@@ -18,31 +20,39 @@ func OptoFIT(comp *Component, mission *Mission) (float64, error) {
 	p := NewPackage(comp.Package)
 	lrh, ltc, lts, lm := p.FitBase()
 
+	r := newResult(1)
+
 	for _, ph := range mission.Phases {
 
-		// TODO Add disipated power
-		tj := ph.Tamb
+		on := mission.IsOn(comp, ph)
+		tamb := mission.Tamb(comp, ph)
 
-		// Physical
-		pi := lth*PiThermal(0.4, tj, ph.On) +
-			ltc*PiTCCase(ph.NCycles, ph.Duration, ph.Tdelta, ph.Tmax) +
-			(lts+ltc_chip)*PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax) +
-			lrh*PiRH2(0.9, ph.RH, ph.Tamb, ph.On) +
-			(lm+lm_chip)*PiMech(ph.Grms)
+		tj, err := phaseTj(comp.InPhase(ph), p, tamb, on)
+		if err != nil {
+			return nil, err
+		}
 
 		// Proportion of time in this phase
-		pi *= ph.Duration / mission.Ttotal
+		w := ph.Duration / mission.Ttotal
+
+		// Physical
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+		pi.Thermal = w * lth * PiThermal(0.4, tj, on)
+		pi.TCycling = w * (ltc*PiTCCase(ph.NCycles, ph.Duration, ph.Tdelta, ph.Tmax) +
+			(lts+ltc_chip)*PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax))
+		pi.Humidity = w * lrh * PiRH2(0.9, ph.RH, tamb, on)
+		pi.Mechanical = w * (lm + lm_chip) * PiMech(ph.Grms)
 
 		// Stress factors and sensibility
 		ifactor, err := PiInduced(comp, ph)
 		if err != nil {
-			return math.NaN(), err
+			return nil, err
 		}
-		pi *= ifactor
+		pi.Induced = ifactor
 
-		factor += pi
+		r.add(pi)
 	}
 
-	return fit * factor * PiPM() * PiProcess(), nil
+	return r, nil
 
 }
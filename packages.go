@@ -60,10 +60,25 @@ func (p *Package) FitBase() (float64, float64, float64, float64) {
 	return p.l0rh, p.l0tcCase, p.l0tcSolder, p.l0mech
 }
 
+// Rtha returns the junction to ambient thermal resistance of the package.
+// Packages not in the database lose their number of pins in the name, so it
+// is added again for the generic (per pin) formula.
 func (p *Package) Rtha(tcSusbtrate float64) float64 {
+	if packages[p.Name] == nil && p.Npins > 0 {
+		return rthja(p.Name+strconv.Itoa(p.Npins), tcSusbtrate)
+	}
 	return rthja(p.Name, tcSusbtrate)
 }
 
+// Rjc returns the junction to case thermal resistance of the package, or NaN
+// if not known.
+func (p *Package) Rjc() float64 {
+	if p.rjc <= 0 {
+		return math.NaN()
+	}
+	return p.rjc
+}
+
 func init() {
 
 	pkgs, _ := csv.ReadString(datacsv)
@@ -6,15 +6,19 @@ import (
 )
 
 func PcbFIT(mission *Mission, nLayers, nConn int) (float64, error) {
+	return fitOf(PcbEval(mission, nLayers, nConn))
+}
 
-	var fit, nfit float64
+func PcbEval(mission *Mission, nLayers, nConn int) (*Result, error) {
 
 	l0 := Lbase_Pcb(nLayers, nConn, 2, 0.25)
 	cs := Cs("PCB", nil)
 	if math.IsNaN(cs) {
-		return math.NaN(), errors.New("Missing data for stress sensibility calculation")
+		return nil, errors.New("Missing data for stress sensibility calculation")
 	}
 
+	r := newResult(l0)
+
 	for _, ph := range mission.Phases {
 
 		prot := 0.0
@@ -22,20 +26,20 @@ func PcbFIT(mission *Mission, nLayers, nConn int) (float64, error) {
 			prot = 1
 		}
 
-		nfit = l0 * ph.Duration / mission.Ttotal *
-			(0.6*PiTV(ph.Tamb)*PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax) +
-				0.18*PiTV(ph.Tamb)*PiRH(0.9, ph.RH, ph.Tamb) +
-				0.02*PiTV(ph.Tamb)*ph.SalinePollution*ph.AmbientPollution*ph.ZonePollution*prot +
-				0.02*PiTV(ph.Tamb)*PiMech(ph.Grms))
+		w := ph.Duration / mission.Ttotal
+		tv := PiTV(ph.Tamb)
 
-		nfit *= PiInducedPcb(ph)
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+		pi.TCycling = w * 0.6 * tv * PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax)
+		pi.Humidity = w * 0.18 * tv * PiRH(0.9, ph.RH, ph.Tamb)
+		pi.Chemical = w * 0.02 * tv * ph.SalinePollution * ph.AmbientPollution * ph.ZonePollution * prot
+		pi.Mechanical = w * 0.02 * tv * PiMech(ph.Grms)
+		pi.Induced = PiInducedPcb(ph)
 
-		fit += nfit
+		r.add(pi)
 	}
 
-	fit *= PiPM() * PiProcess()
-
-	return fit, nil
+	return r, nil
 }
 
 func Lbase_Pcb(nLayers, nConn, class int, tech float64) float64 {
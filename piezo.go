@@ -3,49 +3,57 @@ package fides
 //	"errors"
 import (
 	"errors"
-	"math"
 )
 
 // Current not considered currently
 func PiezoFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(PiezoEval(comp, mission))
+}
+
+func PiezoEval(comp *Component, mission *Mission) (*Result, error) {
 
 	fit, lth, ltc, lm, lrh := lbase_piezo(comp)
-	var factor float64
+	r := newResult(fit)
 
 	for _, ph := range mission.Phases {
 
+		// Local ambient temperature
+		tamb := mission.Tamb(comp, ph)
+
 		// General rule
-		if ph.Tamb > comp.Tmax {
-			return math.NaN(), errors.New("Using component above its Tmax")
+		if tamb > comp.Tmax {
+			return nil, errors.New("Using component above its Tmax")
 		}
 
+		on := mission.IsOn(comp, ph)
+
 		tfactor := 1.0
-		if ph.On == false {
+		if !on {
 			tfactor = 0
-		} else if ph.Tamb+40 > comp.Tmax {
+		} else if tamb+40 > comp.Tmax {
 			tfactor = 5
 		}
 
-		pi := lth*tfactor +
-			ltc*PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax) +
-			lm*PiMech(ph.Grms) +
-			lrh*PiRH2(0.9, ph.RH, ph.Tamb, ph.On)
-
 		// Proportion of time in this phase
-		pi *= ph.Duration / mission.Ttotal
+		w := ph.Duration / mission.Ttotal
+
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+		pi.Thermal = w * lth * tfactor
+		pi.TCycling = w * ltc * PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax)
+		pi.Mechanical = w * lm * PiMech(ph.Grms)
+		pi.Humidity = w * lrh * PiRH2(0.9, ph.RH, tamb, on)
 
 		// Stress factors and sensibility
 		ifactor, err := PiInduced(comp, ph)
 		if err != nil {
-			return math.NaN(), err
+			return nil, err
 		}
-		pi *= ifactor
-
-		factor += pi
+		pi.Induced = ifactor
 
+		r.add(pi)
 	}
 
-	return fit * factor * PiPM() * PiProcess(), nil
+	return r, nil
 
 }
 
@@ -0,0 +1,203 @@
+package fides
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ProtectionFIT returns the FIT of an overvoltage protection device: varistor
+// (class RV), gas discharge tube (class GDT), TVS diode (class D, tag tvs) or
+// multi-line ESD array (tag esd, or tvs and array)
+func ProtectionFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(ProtectionEval(comp, mission))
+}
+
+// ProtectionEval returns the detailed FIT of a protection device.
+//
+// Vmax is the standoff (or maximum continuous) voltage and V the working
+// voltage. Vpmax is the clamping voltage of the device and Vwithstand the
+// maximum transient voltage that the protected circuit withstands. Surge is the energy
+// of each surge (J), Surges the number of surges per hour (or per phase in the
+// loads file) and Emax the rated surge energy.
+//
+// Varistors and GDTs age with repeated surges: Cycles is the number of surges
+// at Emax that the device withstands (default 1 for varistors and 10 for
+// GDTs), increasing with (Emax/Surge)^3 for smaller surges. This ageing is
+// added to the FIT as wear-out (the fraction of the surge life used per
+// hour), and the end of life is also reported, with a warning if it comes
+// before the end of the mission.
+func ProtectionEval(comp *Component, mission *Mission) (*Result, error) {
+
+	if comp.Vmax == 0 || math.IsNaN(comp.Vmax) {
+		return nil, errors.New("Vmax (standoff voltage) not set")
+	}
+
+	ptype := protectionType(comp)
+
+	var r *Result
+	var lth, ea, ltc, lts, lm, lrh float64
+
+	if ptype == "tvs" || ptype == "array" {
+
+		// As a TVS diode, with the package terms of semiconductors
+		chip := *comp
+		chip.Class = "D"
+		chip.Tags = append([]string{"tvs"}, comp.Tags...)
+		lth = Lchip_th(&chip)
+		ea = 0.7
+
+		p := NewPackage(comp.Package)
+		lrh, ltc, lts, lm = p.FitBase()
+		if lrh < 0 || math.IsNaN(lrh) {
+			if IsSmd(comp) {
+				lrh, ltc, lts, lm = 0.0055, 0.00057, 0.00285, 0.000057
+			} else {
+				lrh, ltc, lts, lm = 0.031, 0.001, 0.0055, 0.00011
+			}
+		}
+		r = newResult(1)
+
+	} else {
+		var fit float64
+		fit, ea, lth, lts, lm, lrh = lbase_protection(ptype)
+		r = newResult(fit)
+	}
+
+	emax, life := protectionRating(comp, ptype)
+
+	if comp.Vwithstand > 0 && comp.Vpmax > 0 && comp.Vpmax > 0.9*comp.Vwithstand {
+		r.warn("Clamping voltage (%.1f V) leaves less than 10%% margin to the transient limit of the circuit (%.1f V)", comp.Vpmax, comp.Vwithstand)
+	}
+
+	// Fraction of the surge life used by the mission
+	used := 0.0
+
+	for _, ph := range mission.Phases {
+
+		// Working conditions in this phase
+		c := comp.InPhase(ph)
+		on := mission.IsOn(comp, ph)
+		tamb := mission.Tamb(comp, ph)
+
+		if tamb > comp.Tmax {
+			return nil, errors.New("Using component above its Tmax")
+		}
+		if c.V > comp.Vmax {
+			return nil, errors.New("working V higher than standoff voltage Vmax")
+		}
+		if emax > 0 && c.Surge > emax {
+			s := fmt.Sprintf("Surge energy (%f J) exceeds the rating of the device (%f J)", c.Surge, emax)
+			return nil, errors.New(s)
+		}
+
+		// Proportion of time in this phase
+		w := ph.Duration / mission.Ttotal
+
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+		pi.Thermal = w * lth * PiThermal(ea, tamb, on) * standoffFactor(c.V/comp.Vmax)
+		pi.TCycling = w * (ltc*PiTCCase(ph.NCycles, ph.Duration, ph.Tdelta, ph.Tmax) +
+			lts*PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax))
+		pi.Humidity = w * lrh * PiRH2(0.9, ph.RH, tamb, on)
+		pi.Mechanical = w * lm * PiMech(ph.Grms)
+
+		// Stress factors and sensibility
+		ifactor, err := PiInduced(comp, ph)
+		if err != nil {
+			return nil, err
+		}
+		pi.Induced = ifactor
+
+		// Ageing by repeated surges. Surges also arrive when the equipment
+		// is off.
+		if life > 0 && emax > 0 && c.Surge > 0 && c.Surges > 0 {
+			n := life * math.Pow(emax/c.Surge, 3)
+			pi.Wearout = w * 1e9 * c.Surges / n
+			used += ph.Duration * c.Surges / n
+		}
+
+		r.add(pi)
+	}
+
+	if used > 0 {
+		r.Life = mission.Ttotal / used
+		if r.Life < mission.Ttotal {
+			r.warn("End of life by surges (%.0f h) before the end of the mission (%.0f h)", r.Life, mission.Ttotal)
+		}
+	}
+
+	return r, nil
+}
+
+// isProtection returns true for the components handled by ProtectionEval:
+// varistors, GDTs, and TVS diodes and ESD arrays with a standoff voltage. TVS
+// diodes and ESD arrays without Vmax are evaluated as other diodes and ICs.
+func isProtection(c *Component) bool {
+
+	switch strings.ToUpper(c.Class) {
+	case "RV", "GDT":
+		return true
+	case "D", "U":
+		if c.Vmax == 0 || math.IsNaN(c.Vmax) {
+			return false
+		}
+		return contains(c.Tags, "tvs") || contains(c.Tags, "esd")
+	}
+	return false
+}
+
+// protectionType returns mov, gdt, array or tvs
+func protectionType(c *Component) string {
+
+	switch {
+	case strings.ToUpper(c.Class) == "RV" || contains(c.Tags, "mov") || contains(c.Tags, "varistor"):
+		return "mov"
+	case strings.ToUpper(c.Class) == "GDT" || contains(c.Tags, "gdt"):
+		return "gdt"
+	case contains(c.Tags, "esd") || contains(c.Tags, "array"):
+		return "array"
+	}
+	return "tvs"
+}
+
+// Returns l0, ea, lth, lts, lmech, lrh of varistors and GDTs. Their thermal
+// cycling term is that of the solder joints (lts), as they have no package
+// terms.
+func lbase_protection(ptype string) (float64, float64, float64, float64, float64, float64) {
+
+	if ptype == "gdt" {
+		// Glass or ceramic body, sensitive to leaks
+		return 1.0, 0.3, 0.3, 0.3, 0.1, 0.3
+	}
+	return 0.5, 0.4, 0.6, 0.25, 0.05, 0.1
+}
+
+// protectionRating returns the rated surge energy (Emax, or for TVS the
+// energy of a 10/1000 µs pulse at Pmax) and the number of surges at that
+// energy that ages the device to its end of life (0 if it does not age).
+func protectionRating(c *Component, ptype string) (float64, float64) {
+
+	emax := c.Emax
+	if emax <= 0 && c.Pmax > 0 && (ptype == "tvs" || ptype == "array") {
+		emax = c.Pmax * 1.4e-3
+	}
+
+	life := 0.0
+	switch ptype {
+	case "mov":
+		life = 1
+	case "gdt":
+		life = 10
+	}
+	if c.Cycles > 0 && life > 0 {
+		life = c.Cycles
+	}
+	return emax, life
+}
+
+// standoffFactor increases the thermal term when the working voltage is close
+// to the standoff voltage, where the leakage current grows quickly.
+func standoffFactor(ratio float64) float64 {
+	return 1 + math.Pow(ratio/0.8, 6)
+}
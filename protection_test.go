@@ -0,0 +1,116 @@
+package fides
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsProtection(t *testing.T) {
+
+	tests := []struct {
+		class string
+		tags  []string
+		vmax  float64
+		want  bool
+	}{
+		{"RV", nil, 0, true},
+		{"GDT", nil, 0, true},
+		{"D", []string{"tvs"}, 5, true},
+		{"D", []string{"esd"}, 5, true},
+		{"D", []string{"tvs", "array"}, 5, true},
+		{"U", []string{"esd"}, 5, true},
+		{"D", []string{"tvs"}, 0, false},
+		{"U", []string{"esd"}, 0, false},
+		{"D", []string{"array"}, 75, false},
+		{"D", []string{"zener"}, 5, false},
+		{"Q", []string{"tvs"}, 5, false},
+	}
+
+	for _, tt := range tests {
+		c := &Component{Class: tt.class, Tags: tt.tags, Vmax: tt.vmax}
+		if got := isProtection(c); got != tt.want {
+			t.Errorf("isProtection(%s %v, vmax %g) = %v, want %v", tt.class, tt.tags, tt.vmax, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateProtection(t *testing.T) {
+
+	m := testMission(40)
+
+	tests := []struct {
+		name       string
+		comp       *Component
+		protection bool
+	}{
+		{"tvs", &Component{Class: "D", Tags: []string{"tvs"}, Package: "SOD123", Tmax: 150, Vmax: 5, V: 3.3, Pmax: 400}, true},
+		{"tvs without vmax", &Component{Class: "D", Tags: []string{"tvs"}, Package: "SOD123", Tmax: 150, Pmax: 400}, false},
+		{"esd array without vmax", &Component{Class: "U", Tags: []string{"esd"}, Package: "SOT23-6", Tmax: 150}, false},
+		{"dual signal diode", &Component{Class: "D", Tags: []string{"array"}, Package: "SOT23", Tmax: 150, Vmax: 75, V: 5, Imax: 0.2}, false},
+	}
+
+	for _, tt := range tests {
+
+		got, err := Evaluate(tt.comp, m)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+
+		var want *Result
+		if tt.protection {
+			want, err = ProtectionEval(tt.comp, m)
+		} else {
+			want, err = SemiconductorEval(tt.comp, m)
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got.FIT <= 0 || !near(got.FIT, want.FIT) {
+			t.Errorf("%s: FIT = %g, want %g", tt.name, got.FIT, want.FIT)
+		}
+	}
+}
+
+func TestProtectionEvalErrors(t *testing.T) {
+
+	m := testMission(40)
+
+	tests := []struct {
+		name string
+		comp *Component
+		err  string
+	}{
+		{"no vmax", &Component{Class: "RV", Tmax: 85}, "Vmax"},
+		{"above standoff", &Component{Class: "RV", Tmax: 85, Vmax: 30, V: 40}, "standoff"},
+		{"above tmax", &Component{Class: "GDT", Tmax: 30, Vmax: 90}, "Tmax"},
+		{"surge above rating", &Component{Class: "RV", Tmax: 85, Vmax: 30, V: 24, Emax: 1, Surge: 2}, "exceeds the rating"},
+	}
+
+	for _, tt := range tests {
+		_, err := ProtectionEval(tt.comp, m)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestProtectionSurgeLife(t *testing.T) {
+
+	m := testMission(40)
+
+	c := &Component{Class: "RV", Tmax: 85, Vmax: 30, V: 24, Emax: 10, Surge: 1, Surges: 0.01}
+	r, err := ProtectionEval(c, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// One surge at emax by default, 1000 at a tenth of it
+	if want := 1000 / 0.01; !near(r.Life, want) {
+		t.Errorf("Life = %g, want %g", r.Life, want)
+	}
+	if len(r.Warnings) != 0 {
+		t.Errorf("unexpected warnings %v", r.Warnings)
+	}
+}
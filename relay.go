@@ -0,0 +1,127 @@
+package fides
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// RelayFIT returns the FIT of an electromechanical relay (class RL)
+func RelayFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(RelayEval(comp, mission))
+}
+
+// RelayEval returns the detailed FIT of an electromechanical relay.
+//
+// The coil ages while energized (Duty is the fraction of time it is), and
+// the contacts wear out with the number of operations (Ops per hour, or per
+// phase in the loads file). The wear-out rate follows ISO 13849-1:
+// λ = 0.1 * ops / B10, where B10 (operations until 10% of the relays fail)
+// is the rated electrical life Cycles, reduced for inductive and lamp loads.
+func RelayEval(comp *Component, mission *Mission) (*Result, error) {
+
+	power := relayPower(comp)
+	fit, ltc, lm, lrh, rise, life := lbase_relay(power)
+
+	if comp.Cycles > 0 {
+		life = comp.Cycles
+	}
+	life /= relayLoadFactor(comp.Tags)
+
+	sealed := contains(comp.Tags, "sealed")
+
+	r := newResult(fit)
+
+	for _, ph := range mission.Phases {
+
+		// Working conditions in this phase
+		c := comp.InPhase(ph)
+		on := mission.IsOn(comp, ph)
+		tamb := mission.Tamb(comp, ph)
+
+		if comp.Imax > 0 && c.I > comp.Imax {
+			s := fmt.Sprintf("Contact current (%f A) exceeds its Imax (%f A)", c.I, comp.Imax)
+			return nil, errors.New(s)
+		}
+
+		duty := 0.0
+		if on {
+			duty = math.Min(math.Max(c.Duty, 0), 1)
+		}
+
+		tcoil := tamb + rise*duty
+		if tcoil > comp.Tmax {
+			s := fmt.Sprintf("Coil temperature (%f ºC) exceeds its Tmax (%f ºC)", tcoil, comp.Tmax)
+			return nil, errors.New(s)
+		}
+
+		// Proportion of time in this phase
+		w := ph.Duration / mission.Ttotal
+
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+
+		// Coil, only when energized
+		pi.Thermal = w * 0.3 * duty * Arrhenius25(0.3, tamb+rise)
+
+		pi.TCycling = w * ltc * PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax)
+		pi.Mechanical = w * lm * PiMech(ph.Grms)
+
+		// Contacts, as for connectors
+		pi.Humidity = w * lrh * PiRH(0.8, ph.RH, tamb)
+		pi.Chemical = w * PiChemical(0.2, ph.SalinePollution, ph.AmbientPollution, ph.ZonePollution, ph.IP || sealed)
+
+		// Stress factors and sensibility
+		ifactor, err := PiInduced(comp, ph)
+		if err != nil {
+			return nil, err
+		}
+		pi.Induced = ifactor
+
+		// Contact wear-out
+		if on {
+			pi.Wearout = w * opsWearout(c.Ops, life)
+		}
+
+		r.add(pi)
+	}
+
+	return r, nil
+}
+
+// Power relays: tag power or Imax of 2 A or more. Default is signal relay.
+func relayPower(c *Component) bool {
+	return contains(c.Tags, "power") || c.Imax >= 2
+}
+
+// Returns l0, ltc, lmech, lrh, coil temperature rise and electrical life in
+// operations at rated resistive load
+func lbase_relay(power bool) (float64, float64, float64, float64, float64, float64) {
+
+	if power {
+		return 1.0, 0.3, 0.3, 0.1, 30, 1e5
+	}
+	return 0.5, 0.3, 0.3, 0.1, 15, 1e6
+}
+
+// relayLoadFactor returns the reduction of the electrical life of the
+// contacts for the type of load: resistive (default), inductive or lamp
+func relayLoadFactor(tags []string) float64 {
+
+	if contains(tags, "lamp") || contains(tags, "capacitive") {
+		return 5
+	}
+	if contains(tags, "inductive") || contains(tags, "motor") {
+		return 3
+	}
+	return 1
+}
+
+// opsWearout returns the wear-out FIT of contacts operated ops times per hour,
+// with a rated life of B10 = life operations (ISO 13849-1: λ = 0.1·ops/B10).
+func opsWearout(ops, life float64) float64 {
+
+	if ops <= 0 || math.IsNaN(ops) || life <= 0 {
+		return 0
+	}
+	return 1e9 * 0.1 * ops / life
+}
@@ -0,0 +1,70 @@
+package fides
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOpsWearout(t *testing.T) {
+
+	tests := []struct {
+		ops, life, want float64
+	}{
+		{0, 1e6, 0},
+		{-1, 1e6, 0},
+		{1, 0, 0},
+		{1, 1e6, 100},
+		{10, 1e5, 1e4},
+	}
+
+	for _, tt := range tests {
+		if got := opsWearout(tt.ops, tt.life); !near(got, tt.want) {
+			t.Errorf("opsWearout(%g, %g) = %g, want %g", tt.ops, tt.life, got, tt.want)
+		}
+	}
+}
+
+func TestRelayEval(t *testing.T) {
+
+	m := testMission(40)
+
+	tests := []struct {
+		name    string
+		comp    *Component
+		wearout float64
+	}{
+		{"signal", &Component{Class: "RL", Tmax: 85, Ops: 1}, 100},
+		{"power", &Component{Class: "RL", Tags: []string{"power"}, Tmax: 85, Ops: 1}, 1000},
+		{"rated life", &Component{Class: "RL", Tmax: 85, Ops: 1, Cycles: 2e5, Imax: 5}, 500},
+		{"inductive load", &Component{Class: "RL", Tags: []string{"inductive"}, Tmax: 85, Ops: 1}, 300},
+		{"lamp load", &Component{Class: "RL", Tags: []string{"lamp"}, Tmax: 85, Ops: 1}, 500},
+		{"not operated", &Component{Class: "RL", Tmax: 85}, 0},
+	}
+
+	for _, tt := range tests {
+		r, err := RelayEval(tt.comp, m)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !near(r.Wearout(), tt.wearout) {
+			t.Errorf("%s: wear-out %g, want %g", tt.name, r.Wearout(), tt.wearout)
+		}
+	}
+
+	errs := []struct {
+		name string
+		comp *Component
+		err  string
+	}{
+		{"above imax", &Component{Class: "RL", Tmax: 85, Imax: 1, I: 2}, "Imax"},
+		{"hot coil", &Component{Class: "RL", Tmax: 50, Duty: 1}, "Coil temperature"},
+	}
+
+	for _, tt := range errs {
+		_, err := RelayEval(tt.comp, m)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
@@ -9,78 +9,114 @@ import (
 )
 
 func ResistorFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(ResistorEval(comp, mission))
+}
+
+func ResistorEval(comp *Component, mission *Mission) (*Result, error) {
 
 	// The A parameter from FIDES 2022 is ignored, as we are calculating
 	// the actual temperature of the part based on Rtha.
 	fit, _, lth, ltc, lm, lrh := Lbase_resistor(comp)
-	var factor float64
 
 	// networks
 	if comp.N > 1 {
 		fit *= math.Sqrt(float64(comp.N))
 	}
 
-	// Power calculation. Priority: P, V²/R, I*R
-	if (comp.P == 0 || math.IsNaN(comp.P)) && comp.V != 0 {
-		if comp.Value == 0 {
-			comp.Value = 0.001
-		}
-		comp.P = comp.V * comp.V / comp.Value
-	}
-	if comp.P == 0 || math.IsNaN(comp.P) {
-		if comp.I != 0 && math.IsNaN(comp.I) {
-			comp.P = comp.Value * comp.I
-		}
-	}
-	if comp.P == 0 || math.IsNaN(comp.P) {
-		return math.NaN(), errors.New("Power cannot be calculated. Either set P, V or I")
-	}
-
 	if comp.Pmax == 0 || math.IsNaN(comp.Pmax) {
-		return math.NaN(), errors.New("Pmax is not set")
-	}
-	if comp.P > comp.Pmax {
-		s := fmt.Sprintf("Actual power (%f W) exceeds its Pmax (%f W) R=%g", comp.P, comp.Pmax, comp.Value)
-		return math.NaN(), errors.New(s)
+		return nil, errors.New("Pmax is not set")
 	}
 
-	comp.Rtha = electronics.Rth(comp.Package)
+	if comp.Rtha <= 0 || math.IsNaN(comp.Rtha) {
+		comp.Rtha = electronics.Rth(comp.Package)
+	}
 	if comp.Rtha == 0 {
-		return math.NaN(), errors.New("Rth could not be set for this package")
+		return nil, errors.New("Rth could not be set for this package")
 	}
-	tdelta := comp.P * comp.Rtha
+
+	variant := resistorVariant(comp.Tags)
+
+	r := newResult(fit)
 
 	for _, ph := range mission.Phases {
 
-		tc := ph.Tamb + tdelta
-		if tc >= comp.Tmax && ph.On {
-			s := fmt.Sprintf("Component temperature (%f ºC) exceeds its Tmax (%f ºC), P=%f W, Rth=%f ºC/W ", tc, comp.Tmax, comp.P, comp.Rtha)
-			return math.NaN(), errors.New(s)
-		}
+		// Working conditions in this phase
+		c := comp.InPhase(ph)
+		on := mission.IsOn(comp, ph)
+		tamb := mission.Tamb(comp, ph)
 
-		pi := 0.0
-		if ph.On {
-			pi = lth * Arrhenius25(0.15, tc)
-		}
+		var tc float64
+		var err error
 
-		pi += ltc*PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax) +
-			lrh*PiRH2(0.9, ph.RH, ph.Tamb, ph.On) +
-			lm*PiMech(ph.Grms)
+		switch variant {
+		case "ntc", "ptc":
+			tc, err = thermistorTemp(c, tamb, comp.Rtha, variant)
+		case "shunt":
+			err = shuntPulse(c)
+			if err == nil {
+				err = resistorPower(c)
+			}
+			tc = tamb + c.P*comp.Rtha
+		default:
+			err = resistorPower(c)
+			tc = tamb + c.P*comp.Rtha
+		}
+		if err != nil {
+			return nil, err
+		}
+		if tc >= comp.Tmax && on {
+			s := fmt.Sprintf("Component temperature (%f ºC) exceeds its Tmax (%f ºC), P=%f W, Rth=%f ºC/W ", tc, comp.Tmax, c.P, comp.Rtha)
+			return nil, errors.New(s)
+		}
 
 		// Proportion of time in this phase
-		pi *= ph.Duration / mission.Ttotal
+		w := ph.Duration / mission.Ttotal
+
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+		if on {
+			pi.Thermal = w * lth * Arrhenius25(0.15, tc)
+		}
+		pi.TCycling = w * ltc * PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax)
+		pi.Humidity = w * lrh * PiRH2(0.9, ph.RH, tamb, on)
+		pi.Mechanical = w * lm * PiMech(ph.Grms)
 
 		// Stress factors and sensibility
 		ifactor, err := PiInduced(comp, ph)
 		if err != nil {
-			return math.NaN(), err
+			return nil, err
 		}
-		pi *= ifactor
+		pi.Induced = ifactor
 
-		factor += pi
+		r.add(pi)
 	}
 
-	return fit * factor * PiPM() * PiProcess(), nil
+	return r, nil
+}
+
+// resistorPower sets the power of a resistor if not given.
+// Priority: P, V²/R, I²R
+func resistorPower(c *Component) error {
+
+	if (c.P == 0 || math.IsNaN(c.P)) && c.V != 0 {
+		if c.Value == 0 {
+			c.Value = 0.001
+		}
+		c.P = c.V * c.V / c.Value
+	}
+	if c.P == 0 || math.IsNaN(c.P) {
+		if c.I != 0 && !math.IsNaN(c.I) {
+			c.P = c.Value * c.I * c.I
+		}
+	}
+	if c.P == 0 || math.IsNaN(c.P) {
+		return errors.New("Power cannot be calculated. Either set P, V or I")
+	}
+
+	if c.P > c.Pmax {
+		s := fmt.Sprintf("Actual power (%f W) exceeds its Pmax (%f W) R=%g", c.P, c.Pmax, c.Value)
+		return errors.New(s)
+	}
+	return nil
 }
 
 // Return base values: l0, A, lth, ltc, lmech, lrh
@@ -124,12 +160,20 @@ func Lbase_resistor(c *Component) (float64, float64, float64, float64, float64,
 
 	}
 
-	// Default: smd thin film resistor
-
-	if !contains(c.Tags, "thin") {
-		c.Tags = append(c.Tags, "thin")
+	// Thermistors: the ceramic body and its glass or epoxy coating are
+	// sensitive to humidity and thermal cycling.
+	switch resistorVariant(c.Tags) {
+	case "ntc":
+		return 0.3, 85, 0.2, 0.5, 0.05, 0.25
+	case "ptc":
+		return 0.3, 85, 0.25, 0.5, 0.05, 0.2
+	case "shunt":
+		// Metal strip or metal plate: the joints dominate
+		return 0.1, 130, 0.1, 0.8, 0.05, 0.05
 	}
 
+	// Default: smd thin film resistor
+
 	if c.Value < 10000 {
 		return 0.18, 85, 0.14, 0.53, 0.07, 0.26
 	} else if c.Value < 100000 {
@@ -138,3 +182,115 @@ func Lbase_resistor(c *Component) (float64, float64, float64, float64, float64,
 		return 0.25, 85, 0.07, 0.55, 0.05, 0.33
 	}
 }
+
+// resistorVariant returns ntc, ptc, shunt (low ohmic current sense resistor)
+// or "" for other resistors
+func resistorVariant(tags []string) string {
+
+	for _, tag := range tags {
+		switch tag {
+		case "ntc":
+			return "ntc"
+		case "ptc", "kty":
+			return "ptc"
+		case "shunt", "sense":
+			return "shunt"
+		}
+	}
+	return ""
+}
+
+// B constant (K) of NTC thermistors and temperature coefficient (ppm/ºC) of
+// linear silicon PTCs, if TC is not given
+const (
+	ntcB  = 3950.0
+	ptcTC = 7000.0
+)
+
+// thermistorTemp returns the temperature of a thermistor heated by its own
+// dissipation. The resistance changes with temperature: an NTC driven by a
+// voltage (or a PTC driven by a current) heats more as it gets hotter, which
+// is solved by iteration. P is set to the final dissipation. A thermistor
+// without V, I or P is taken as unloaded.
+func thermistorTemp(c *Component, tamb, rth float64, variant string) (float64, error) {
+
+	r25 := c.Value
+	if r25 <= 0 {
+		r25 = 10000
+	}
+
+	rt := func(t float64) float64 {
+		if variant == "ntc" {
+			// TC (negative) is the coefficient at 25 ºC: B = -TC * T²
+			b := ntcB
+			if c.TC < 0 {
+				b = -c.TC * 1e-6 * 298.15 * 298.15
+			}
+			return r25 * math.Exp(b*(1/(t+273.15)-1/298.15))
+		}
+		tc := ptcTC
+		if c.TC > 0 {
+			tc = c.TC
+		}
+		return r25 * (1 + tc*1e-6*(t-25))
+	}
+
+	power := func(t float64) float64 {
+		switch {
+		case c.P > 0:
+			return c.P
+		case c.V > 0:
+			return c.V * c.V / rt(t)
+		case c.I > 0:
+			return c.I * c.I * rt(t)
+		}
+		return 0
+	}
+
+	t := tamb
+	for i := 0; i < 50; i++ {
+		tn := tamb + power(t)*rth
+		if tn > c.Tmax+100 {
+			s := fmt.Sprintf("Thermal runaway of thermistor (above %f ºC), Rth=%f ºC/W", c.Tmax, rth)
+			return 0, errors.New(s)
+		}
+		if math.Abs(tn-t) < 0.01 {
+			t = tn
+			break
+		}
+		t = tn
+	}
+
+	c.P = power(t)
+	if c.P > c.Pmax {
+		s := fmt.Sprintf("Actual power (%f W) exceeds its Pmax (%f W) R=%g", c.P, c.Pmax, rt(t))
+		return 0, errors.New(s)
+	}
+	return t, nil
+}
+
+// Thermal time constant (s) of a shunt, if not known
+const shuntTau = 1.0
+
+// shuntPulse checks the pulse power of a current sense resistor. Ip is the
+// peak current of a pulse of length Tp (s). For pulses shorter than the
+// thermal time constant, the element absorbs the energy: the allowed pulse
+// power is Pmax * sqrt(tau/Tp).
+func shuntPulse(c *Component) error {
+
+	if c.Ip <= 0 || c.Tp <= 0 {
+		return nil
+	}
+
+	pp := c.Ip * c.Ip * c.Value
+	max := c.Pmax
+	if c.Tp < shuntTau {
+		max *= math.Sqrt(shuntTau / c.Tp)
+	}
+
+	if pp > max {
+		s := fmt.Sprintf("Pulse power (%f W, %g s) exceeds the pulse capability of the shunt (%f W)", pp, c.Tp, max)
+		return errors.New(s)
+	}
+	return nil
+}
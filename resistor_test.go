@@ -0,0 +1,137 @@
+package fides
+
+import (
+	"math"
+	"testing"
+)
+
+func TestResistorPower(t *testing.T) {
+
+	nan := math.NaN()
+
+	tests := []struct {
+		name         string
+		p, v, i, val float64
+		want         float64
+		err          bool
+	}{
+		{"p", 0.1, 5, 0.1, 100, 0.1, false},
+		{"v", nan, 5, nan, 1000, 0.025, false},
+		{"i", nan, 0, 0.01, 100, 0.01, false},
+		{"i, p zero", 0, 0, 0.02, 100, 0.04, false},
+		{"v before i", 0, 1, 1, 100, 0.01, false},
+		{"nothing", nan, 0, nan, 100, nan, true},
+		{"above pmax", nan, 0, 0.1, 100, nan, true},
+	}
+
+	for _, tt := range tests {
+		c := &Component{P: tt.p, V: tt.v, I: tt.i, Value: tt.val, Pmax: 0.25}
+		err := resistorPower(c)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%s: no error, P = %g", tt.name, c.P)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !near(c.P, tt.want) {
+			t.Errorf("%s: P = %g, want %g", tt.name, c.P, tt.want)
+		}
+	}
+}
+
+func TestResistorVariant(t *testing.T) {
+
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{[]string{"thick"}, ""},
+		{[]string{"ntc"}, "ntc"},
+		{[]string{"kty"}, "ptc"},
+		{[]string{"ptc"}, "ptc"},
+		{[]string{"metal", "sense"}, "shunt"},
+	}
+
+	for _, tt := range tests {
+		if got := resistorVariant(tt.tags); got != tt.want {
+			t.Errorf("resistorVariant(%v) = %q, want %q", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestThermistorTemp(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		variant string
+		comp    *Component
+		rth     float64
+	}{
+		{"unloaded", "ntc", &Component{Value: 10000}, 300},
+		{"power", "ntc", &Component{Value: 10000, P: 0.01}, 300},
+		{"ntc with voltage", "ntc", &Component{Value: 1000, V: 3}, 300},
+		{"ntc with tc", "ntc", &Component{Value: 1000, V: 3, TC: -44000}, 300},
+		{"ptc with current", "ptc", &Component{Value: 1000, I: 0.005}, 300},
+	}
+
+	for _, tt := range tests {
+
+		c := tt.comp
+		c.Tmax, c.Pmax = 150, 0.25
+
+		temp, err := thermistorTemp(c, 40, tt.rth, tt.variant)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+
+		// The temperature is that of the final dissipation
+		if math.Abs(temp-(40+c.P*tt.rth)) > 0.05 {
+			t.Errorf("%s: T = %g, with P = %g", tt.name, temp, c.P)
+		}
+	}
+
+	// An NTC heats more than a fixed resistor of the same value at 25 ºC
+	c := &Component{Value: 1000, V: 3, Tmax: 150, Pmax: 0.25}
+	temp, _ := thermistorTemp(c, 40, 300, "ntc")
+	if temp <= 40+9.0/1000*300 {
+		t.Errorf("NTC at %g ºC, not above a fixed resistor", temp)
+	}
+
+	// Thermal runaway and power above the rating
+	errs := []*Component{
+		{Value: 100, V: 5, Tmax: 150, Pmax: 10},
+		{Value: 1000, V: 10, Tmax: 300, Pmax: 0.1},
+	}
+	for _, c := range errs {
+		if _, err := thermistorTemp(c, 40, 300, "ntc"); err == nil {
+			t.Errorf("no error for %g V on %g Ω", c.V, c.Value)
+		}
+	}
+}
+
+func TestShuntPulse(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		ip, tp float64
+		err    bool
+	}{
+		{"no pulse", 0, 0, false},
+		{"long pulse", 5, 2, false},
+		{"long pulse above pmax", 8, 2, true},
+		{"short pulse", 20, 0.01, false},
+		{"short pulse above capability", 40, 0.01, true},
+	}
+
+	for _, tt := range tests {
+		c := &Component{Value: 0.01, Pmax: 0.5, Ip: tt.ip, Tp: tt.tp}
+		if err := shuntPulse(c); (err != nil) != tt.err {
+			t.Errorf("%s: error %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package fides
+
+import "fmt"
+
+// Contribution holds the physical contributions of one mission phase to the
+// FIT of a component. Each term is already weighted by the proportion of time
+// spent in the phase, but not yet multiplied by the base lambda or by Induced.
+type Contribution struct {
+	Phase string `json:"phase"`
+	// Proportion of the mission time spent in this phase
+	Weight float64 `json:"weight"`
+
+	Thermal    float64 `json:"thermal"`
+	TCycling   float64 `json:"tcycling"`
+	Mechanical float64 `json:"mechanical"`
+	Humidity   float64 `json:"humidity"`
+	Chemical   float64 `json:"chemical"`
+
+	// Pi_induced factor for this phase
+	Induced float64 `json:"induced"`
+
+	// Wear-out, directly in FIT (weighted, but not multiplied by the base
+	// lambda, Pi_induced or the process factors)
+	Wearout float64 `json:"wearout,omitempty"`
+}
+
+// Sum returns the total constant rate contribution of the phase, including
+// Pi_induced. Wear-out is not included.
+func (c *Contribution) Sum() float64 {
+	return (c.Thermal + c.TCycling + c.Mechanical + c.Humidity + c.Chemical) * c.Induced
+}
+
+// Result is the outcome of a FIT evaluation, with enough detail to see which
+// mechanism and which phase drive the failure rate of a component.
+type Result struct {
+	Base      float64         `json:"base"` // Base lambda (l0). 1 for models where the phase terms are already in FIT
+	Phases    []*Contribution `json:"phases"`
+	PiPM      float64         `json:"pi_pm"`
+	PiProcess float64         `json:"pi_process"`
+
+	FIT float64 `json:"fit"`
+
+	// End of life (h) by wear-out when the mission profile is repeated, if
+	// the model calculates it. Not included in the FIT.
+	Life float64 `json:"life,omitempty"`
+
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Wear-out (FIT) per mechanism, for models with several (DSM ICs)
+	Mechanisms map[string]float64 `json:"wearout_mechanisms,omitempty"`
+}
+
+func newResult(base float64) *Result {
+	return &Result{Base: base, PiPM: PiPM(), PiProcess: PiProcess()}
+}
+
+// add appends the contribution of a phase and updates the total FIT.
+func (r *Result) add(c *Contribution) {
+	r.Phases = append(r.Phases, c)
+	r.FIT = r.Constant() + r.Wearout()
+}
+
+// warn adds a warning to the result
+func (r *Result) warn(format string, a ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, a...))
+}
+
+// mechanism adds the wear-out of a mechanism to the total of the result
+func (r *Result) mechanism(name string, fit float64) {
+	if r.Mechanisms == nil {
+		r.Mechanisms = make(map[string]float64)
+	}
+	r.Mechanisms[name] += fit
+}
+
+// Constant returns the constant failure rate part of the FIT (FIDES)
+func (r *Result) Constant() float64 {
+	return r.fit(func(c *Contribution) float64 { return c.Sum() })
+}
+
+// Wearout returns the part of the FIT due to wear-out
+func (r *Result) Wearout() float64 {
+
+	var sum float64
+	for _, c := range r.Phases {
+		sum += c.Wearout
+	}
+	return sum
+}
+
+// fit returns the FIT corresponding to the selected part of each phase
+// contribution (before Pi_induced).
+func (r *Result) fit(f func(c *Contribution) float64) float64 {
+
+	var sum float64
+	for _, c := range r.Phases {
+		sum += f(c)
+	}
+	return r.Base * sum * r.PiPM * r.PiProcess
+}
+
+// Thermal returns the part of the FIT due to thermal stress
+func (r *Result) Thermal() float64 {
+	return r.fit(func(c *Contribution) float64 { return c.Thermal * c.Induced })
+}
+
+// TCycling returns the part of the FIT due to thermal cycling (case and solder joints)
+func (r *Result) TCycling() float64 {
+	return r.fit(func(c *Contribution) float64 { return c.TCycling * c.Induced })
+}
+
+// Mechanical returns the part of the FIT due to vibrations
+func (r *Result) Mechanical() float64 {
+	return r.fit(func(c *Contribution) float64 { return c.Mechanical * c.Induced })
+}
+
+// Humidity returns the part of the FIT due to humidity
+func (r *Result) Humidity() float64 {
+	return r.fit(func(c *Contribution) float64 { return c.Humidity * c.Induced })
+}
+
+// Chemical returns the part of the FIT due to chemical pollution
+func (r *Result) Chemical() float64 {
+	return r.fit(func(c *Contribution) float64 { return c.Chemical * c.Induced })
+}
+
+// PhaseFIT returns the part of the FIT due to phase i
+func (r *Result) PhaseFIT(i int) float64 {
+	if i < 0 || i >= len(r.Phases) {
+		return 0
+	}
+	return r.Base*r.Phases[i].Sum()*r.PiPM*r.PiProcess + r.Phases[i].Wearout
+}
+
+// Dominant returns the name of the phase with the highest contribution
+func (r *Result) Dominant() string {
+
+	name := ""
+	max := -1.0
+	for i, c := range r.Phases {
+		if f := r.PhaseFIT(i); f > max {
+			max = f
+			name = c.Phase
+		}
+	}
+	return name
+}
@@ -0,0 +1,101 @@
+package fides
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResult(t *testing.T) {
+
+	r := &Result{Base: 2, PiPM: 1.5, PiProcess: 2}
+	r.add(&Contribution{Phase: "day", Weight: 0.5, Thermal: 1, TCycling: 2, Mechanical: 3, Humidity: 4, Chemical: 5, Induced: 2})
+	r.add(&Contribution{Phase: "night", Weight: 0.5, Thermal: 1, Induced: 1, Wearout: 100})
+
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"thermal", r.Thermal(), 2 * 1.5 * 2 * (2 + 1)},
+		{"tcycling", r.TCycling(), 6 * 4},
+		{"mechanical", r.Mechanical(), 6 * 6},
+		{"humidity", r.Humidity(), 6 * 8},
+		{"chemical", r.Chemical(), 6 * 10},
+		{"wearout", r.Wearout(), 100},
+		{"constant", r.Constant(), 6 * 31},
+		{"fit", r.FIT, 6*31 + 100},
+		{"phase 0", r.PhaseFIT(0), 6 * 30},
+		{"phase 1", r.PhaseFIT(1), 6 + 100},
+		{"phase out of range", r.PhaseFIT(2), 0},
+	}
+
+	for _, tt := range tests {
+		if !near(tt.got, tt.want) {
+			t.Errorf("%s = %g, want %g", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if d := r.Dominant(); d != "day" {
+		t.Errorf("Dominant() = %s, want day", d)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+
+	m := testMission(40)
+
+	tests := []struct {
+		name string
+		comp *Component
+	}{
+		{"ic", &Component{Class: "U", Tags: []string{"digital"}, Package: "SOT23-5", Tmax: 125}},
+		{"transistor", &Component{Class: "Q", Package: "SOT23", Tmax: 150, Pmax: 0.3, P: 0.1}},
+		{"opto", &Component{Class: "U", Tags: []string{"opto"}, Package: "SOT23-6", Tmax: 110}},
+		{"resistor", &Component{Class: "R", Tags: []string{"thick"}, Package: "0805", Tmax: 155, Pmax: 0.125, P: 0.05}},
+		{"capacitor", &Component{Class: "C", Tags: []string{"x7r"}, Package: "0805", Tmax: 125, Vmax: 50, V: 5, Value: 100e-9}},
+		{"inductor", &Component{Class: "L", Tags: []string{"power"}, Tmax: 125}},
+	}
+
+	for _, tt := range tests {
+
+		r, err := Evaluate(tt.comp, m)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if r.FIT <= 0 {
+			t.Errorf("%s: FIT = %g, want > 0", tt.name, r.FIT)
+		}
+
+		sum := r.Thermal() + r.TCycling() + r.Mechanical() + r.Humidity() + r.Chemical() + r.Wearout()
+		if !near(sum, r.FIT) {
+			t.Errorf("%s: sum of the mechanisms %g, FIT %g", tt.name, sum, r.FIT)
+		}
+
+		fit, err := FIT(tt.comp, m)
+		if err != nil || fit != r.FIT {
+			t.Errorf("%s: FIT() = %g, %v, want %g", tt.name, fit, err, r.FIT)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+
+	m := testMission(40)
+
+	tests := []struct {
+		name string
+		comp *Component
+		err  string
+	}{
+		{"no tmax", &Component{Class: "R", Package: "0805", Pmax: 0.125, P: 0.05}, "Tmax"},
+		{"unknown zone", &Component{Class: "R", Package: "0805", Tmax: 155, Pmax: 0.125, P: 0.05, Zone: "hot"}, "zone"},
+		{"unknown class", &Component{Class: "ZZ", Tmax: 125}, ""},
+	}
+
+	for _, tt := range tests {
+		_, err := Evaluate(tt.comp, m)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package fides
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// RfFIT returns the FIT of an RF or microwave component (class RF)
+func RfFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(RfEval(comp, mission))
+}
+
+// RfEval returns the detailed FIT of an RF component: MMICs (tag mmic), RF
+// power transistors (pa), SAW and BAW filters (saw, baw), circulators and
+// isolators (circulator, isolator), coaxial connectors (connector) and
+// attenuators (attenuator).
+//
+// Prf is the RF power (output power of active devices, input power of
+// passive ones), Prfmax its rating and Freq the frequency. The power ratio
+// and the frequency band are stress factors of the thermal term. Active
+// devices use the GaAs, GaN or LDMOS die rates of lchip_rf and the package
+// database; the dissipation is P if given, else derived from Prf and the
+// typical efficiency of the technology.
+func RfEval(comp *Component, mission *Mission) (*Result, error) {
+
+	rtype := rfType(comp.Tags)
+
+	if rtype == "connector" {
+		return rfConnectorEval(comp, mission)
+	}
+
+	var lth, ea, ltc, lts, lm, lrh float64
+	var p *Package
+	var r *Result
+
+	active := rtype == "mmic" || rtype == "pa"
+
+	if active {
+		lth = lchip_rf(rfTech(comp.Tags), comp.Pmax)
+		if rtype == "mmic" {
+			// Several transistors and passives on the die
+			lth *= 2
+		}
+		ea = 0.7
+
+		p = NewPackage(comp.Package)
+		lrh, ltc, lts, lm = p.FitBase()
+		if lrh < 0 || math.IsNaN(lrh) {
+			return nil, errors.New("Missing data for lpkg(rh,tc...) calculation for package: [" + p.Name + "]")
+		}
+		r = newResult(1)
+	} else {
+		var fit float64
+		fit, ea, lth, lts, lm, lrh = lbase_rf(rtype)
+		r = newResult(fit)
+	}
+
+	kf := rfFrequencyFactor(comp.Freq)
+
+	for _, ph := range mission.Phases {
+
+		// Working conditions in this phase
+		c := comp.InPhase(ph)
+		on := mission.IsOn(comp, ph)
+		tamb := mission.Tamb(comp, ph)
+
+		if comp.Prfmax > 0 && c.Prf > comp.Prfmax {
+			s := fmt.Sprintf("RF power (%f W) exceeds its rating (%f W)", c.Prf, comp.Prfmax)
+			return nil, errors.New(s)
+		}
+
+		ratio := 0.0
+		if comp.Prfmax > 0 && on {
+			ratio = c.Prf / comp.Prfmax
+		}
+
+		// Temperature of the die or of the part
+		t := tamb
+		if on {
+			cp := *c
+			cp.P = rfDissipation(c, rtype)
+			if active {
+				tj, err := phaseTj(&cp, p, tamb, on)
+				if err != nil {
+					return nil, err
+				}
+				t = tj
+			} else if comp.Rtha > 0 {
+				t = tamb + cp.P*comp.Rtha
+			}
+		}
+		if t > comp.Tmax {
+			s := fmt.Sprintf("Component temperature (%f ºC) exceeds its Tmax (%f ºC)", t, comp.Tmax)
+			return nil, errors.New(s)
+		}
+
+		// Proportion of time in this phase
+		w := ph.Duration / mission.Ttotal
+
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+		pi.Thermal = w * lth * PiThermal(ea, t, on) * kf * (1 + math.Pow(2*ratio, 3))
+		pi.TCycling = w * (ltc*PiTCCase(ph.NCycles, ph.Duration, ph.Tdelta, ph.Tmax) +
+			lts*PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax))
+		pi.Humidity = w * lrh * PiRH2(0.9, ph.RH, tamb, on)
+		pi.Mechanical = w * lm * PiMech(ph.Grms)
+
+		// Stress factors and sensibility
+		ifactor, err := PiInduced(comp, ph)
+		if err != nil {
+			return nil, err
+		}
+		pi.Induced = ifactor
+
+		r.add(pi)
+	}
+
+	return r, nil
+}
+
+// rfConnectorEval returns the detailed FIT of a coaxial connector: as other
+// connectors, with the wear-out of the matings (Ops per hour, Cycles the rated
+// number of matings, default 500).
+func rfConnectorEval(comp *Component, mission *Mission) (*Result, error) {
+
+	life := 500.0
+	if comp.Cycles > 0 {
+		life = comp.Cycles
+	}
+
+	r := newResult(0.5)
+
+	for _, ph := range mission.Phases {
+
+		// Working conditions in this phase
+		c := comp.InPhase(ph)
+		on := mission.IsOn(comp, ph)
+		tamb := mission.Tamb(comp, ph)
+
+		if tamb > comp.Tmax {
+			return nil, errors.New("Using component above its Tmax")
+		}
+		if comp.Prfmax > 0 && c.Prf > comp.Prfmax {
+			s := fmt.Sprintf("RF power (%f W) exceeds its rating (%f W)", c.Prf, comp.Prfmax)
+			return nil, errors.New(s)
+		}
+
+		// Proportion of time in this phase
+		w := ph.Duration / mission.Ttotal
+
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+		pi.Thermal = w * 0.4 * PiThermal(0.1, tamb, on)
+		pi.TCycling = w * 0.2 * PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax)
+
+		// Mechanical, humidity and chemical, as for connectors
+		contactTerms(pi, w, ph, tamb, ph.IP)
+
+		// Stress factors and sensibility
+		ifactor, err := PiInduced(comp, ph)
+		if err != nil {
+			return nil, err
+		}
+		pi.Induced = ifactor
+
+		pi.Wearout = w * opsWearout(c.Ops, life)
+
+		r.add(pi)
+	}
+
+	return r, nil
+}
+
+// rfType returns mmic (default), pa, saw, baw, circulator, connector or
+// attenuator
+func rfType(tags []string) string {
+
+	for _, tag := range tags {
+		switch tag {
+		case "pa", "ldmos":
+			return "pa"
+		case "saw", "baw":
+			return tag
+		case "circulator", "isolator":
+			return "circulator"
+		case "connector", "coax":
+			return "connector"
+		case "attenuator":
+			return "attenuator"
+		}
+	}
+	return "mmic"
+}
+
+// rfTech returns the die technology of active RF parts: gan, mos (LDMOS, SiGe
+// and CMOS) or gaas (default)
+func rfTech(tags []string) string {
+
+	if contains(tags, "gan") {
+		return "gan"
+	}
+	if contains(tags, "ldmos") || contains(tags, "sige") || contains(tags, "cmos") {
+		return "mos"
+	}
+	return "gaas"
+}
+
+// lchip_rf returns the thermal base lambda of the die of an active RF part, as
+// for transistors of the same technology.
+func lchip_rf(tech string, pmax float64) float64 {
+
+	switch tech {
+	case "gan":
+		return 0.3033
+	case "mos":
+		if pmax >= 5 {
+			return 0.56
+		}
+		return 0.0145
+	}
+	return 0.3756
+}
+
+// Returns l0, ea, lth, ltc, lmech, lrh of RF passives
+func lbase_rf(rtype string) (float64, float64, float64, float64, float64, float64) {
+
+	switch rtype {
+	case "saw":
+		// Acoustomigration of the electrodes
+		return 2.0, 0.5, 0.4, 0.3, 0.2, 0.1
+	case "baw":
+		return 1.5, 0.5, 0.4, 0.3, 0.2, 0.1
+	case "circulator":
+		// Ferrite and magnet, bonded
+		return 1.0, 0.3, 0.3, 0.4, 0.2, 0.1
+	}
+
+	// attenuator, thin film
+	return 0.2, 0.15, 0.4, 0.4, 0.05, 0.15
+}
+
+// rfDissipation returns the power dissipated by an RF part: P if given, else
+// from Prf with the typical efficiency (active) or loss (passive).
+func rfDissipation(c *Component, rtype string) float64 {
+
+	if c.P > 0 {
+		return c.P
+	}
+
+	switch rtype {
+	case "mmic", "pa":
+		eff := 0.4
+		switch rfTech(c.Tags) {
+		case "gan":
+			eff = 0.6
+		case "mos":
+			eff = 0.5
+		}
+		return c.Prf * (1/eff - 1)
+	case "attenuator":
+		// Value is the attenuation in dB
+		return c.Prf * (1 - math.Pow(10, -c.Value/10))
+	case "circulator":
+		return c.Prf * 0.07 // 0.3 dB
+	}
+	return c.Prf * 0.4 // Filters, 2 dB
+}
+
+// rfFrequencyFactor returns the stress of the frequency band: 1 up to 1 GHz,
+// growing by 1 per decade above (smaller geometries, higher current density).
+func rfFrequencyFactor(f float64) float64 {
+
+	if f <= 1e9 || math.IsNaN(f) {
+		return 1
+	}
+	return 1 + math.Log10(f/1e9)
+}
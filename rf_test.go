@@ -0,0 +1,123 @@
+package fides
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLchipRf(t *testing.T) {
+
+	tests := []struct {
+		tech string
+		pmax float64
+		want float64
+	}{
+		{"gaas", 0, 0.3756},
+		{"gaas", 10, 0.3756},
+		{"gan", 10, 0.3033},
+		{"mos", 1, 0.0145},
+		{"mos", 5, 0.56},
+	}
+
+	for _, tt := range tests {
+		if got := lchip_rf(tt.tech, tt.pmax); got != tt.want {
+			t.Errorf("lchip_rf(%s, %g) = %g, want %g", tt.tech, tt.pmax, got, tt.want)
+		}
+	}
+}
+
+func TestRfTech(t *testing.T) {
+
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{nil, "gaas"},
+		{[]string{"mmic", "gan"}, "gan"},
+		{[]string{"pa", "ldmos"}, "mos"},
+		{[]string{"sige"}, "mos"},
+		{[]string{"cmos"}, "mos"},
+	}
+
+	for _, tt := range tests {
+		if got := rfTech(tt.tags); got != tt.want {
+			t.Errorf("rfTech(%v) = %s, want %s", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestRfFrequencyFactor(t *testing.T) {
+
+	tests := []struct {
+		f, want float64
+	}{
+		{0, 1},
+		{500e6, 1},
+		{1e9, 1},
+		{10e9, 2},
+		{100e9, 3},
+	}
+
+	for _, tt := range tests {
+		if got := rfFrequencyFactor(tt.f); !near(got, tt.want) {
+			t.Errorf("rfFrequencyFactor(%g) = %g, want %g", tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestRfEval(t *testing.T) {
+
+	m := testMission(40)
+
+	tests := []struct {
+		name string
+		comp *Component
+		err  string
+	}{
+		{"mmic", &Component{Class: "RF", Tags: []string{"mmic"}, Package: "SOT23-6", Tmax: 150, Prf: 0.1, Prfmax: 1, Freq: 2.4e9}, ""},
+		{"gan pa", &Component{Class: "RF", Tags: []string{"pa", "gan"}, Package: "SOT89", Tmax: 150, Prf: 1, Prfmax: 2, Pmax: 10}, ""},
+		{"saw", &Component{Class: "RF", Tags: []string{"saw"}, Tmax: 85, Prf: 0.01}, ""},
+		{"connector", &Component{Class: "RF", Tags: []string{"coax"}, Tmax: 85, Ops: 0.01}, ""},
+		{"over rating", &Component{Class: "RF", Tags: []string{"saw"}, Tmax: 85, Prf: 2, Prfmax: 1}, "exceeds its rating"},
+		{"over tmax", &Component{Class: "RF", Tags: []string{"attenuator"}, Tmax: 85, Prf: 1, Value: 10, Rtha: 100}, "exceeds its Tmax"},
+		{"connector over tmax", &Component{Class: "RF", Tags: []string{"coax"}, Tmax: 30}, "above its Tmax"},
+	}
+
+	for _, tt := range tests {
+		r, err := RfEval(tt.comp, m)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: error %v, want %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if r.FIT <= 0 {
+			t.Errorf("%s: FIT = %g, want > 0", tt.name, r.FIT)
+		}
+	}
+}
+
+func TestRfEvalStress(t *testing.T) {
+
+	m := testMission(40)
+
+	fit := func(prf, freq float64) float64 {
+		c := &Component{Class: "RF", Tags: []string{"saw"}, Tmax: 85, Prf: prf, Prfmax: 1, Freq: freq}
+		r, err := RfEval(c, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r.FIT
+	}
+
+	if fit(0.5, 1e9) <= fit(0.1, 1e9) {
+		t.Error("the FIT does not increase with the RF power")
+	}
+	if fit(0.1, 10e9) <= fit(0.1, 1e9) {
+		t.Error("the FIT does not increase with the frequency")
+	}
+}
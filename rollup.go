@@ -0,0 +1,134 @@
+package fides
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Group is the FIT of a set of components that share a block, class or tag
+type Group struct {
+	Name  string
+	Count int
+	FIT   float64
+	Share float64      // Fraction of the total FIT of the BOM
+	Top   []*Component // Components with the highest FIT, in descending order
+}
+
+// Rollup aggregates the FIT of the components by "block", "class" or "tag".
+// The FIT of each component must be already calculated (Component.FIT).
+// Components with a NaN FIT (not calculated) are counted but not summed.
+// A component with several tags counts in each of them, so the shares of
+// tag groups can add up to more than 1.
+//
+// The groups are returned in descending order of FIT, with up to ntop
+// components in each Top list.
+func (bom *Bom) Rollup(by string, ntop int) ([]*Group, error) {
+
+	var keys func(c *Component) []string
+
+	switch strings.ToLower(by) {
+	case "block":
+		keys = func(c *Component) []string { return []string{c.Block} }
+	case "class":
+		keys = func(c *Component) []string { return []string{c.Class} }
+	case "tag", "tags":
+		keys = func(c *Component) []string { return c.Tags }
+	default:
+		return nil, errors.New("unknown rollup field " + by)
+	}
+
+	groups := make(map[string]*Group)
+	var total float64
+
+	for _, c := range bom.Components {
+
+		if !math.IsNaN(c.FIT) {
+			total += c.FIT
+		}
+
+		for _, key := range keys(c) {
+			g := groups[key]
+			if g == nil {
+				g = &Group{Name: key}
+				groups[key] = g
+			}
+			g.Count++
+			if !math.IsNaN(c.FIT) {
+				g.FIT += c.FIT
+				g.Top = append(g.Top, c)
+			}
+		}
+	}
+
+	var gg []*Group
+
+	for _, g := range groups {
+
+		if total > 0 {
+			g.Share = g.FIT / total
+		}
+
+		sort.SliceStable(g.Top, func(i, j int) bool { return g.Top[i].FIT > g.Top[j].FIT })
+		if len(g.Top) > ntop {
+			g.Top = g.Top[:ntop]
+		}
+
+		gg = append(gg, g)
+	}
+
+	sort.Slice(gg, func(i, j int) bool {
+		if gg[i].FIT == gg[j].FIT {
+			return gg[i].Name < gg[j].Name
+		}
+		return gg[i].FIT > gg[j].FIT
+	})
+
+	return gg, nil
+}
+
+func GroupsToCsv(by string, groups []*Group) string {
+
+	if by == "" {
+		by = "group"
+	}
+	s := by + ", count, fit, share, top\n"
+
+	for _, g := range groups {
+		s += fmt.Sprintf("%s, ", g.Name)
+		s += fmt.Sprintf("%d, ", g.Count)
+		s += fmt.Sprintf("%.4f, ", g.FIT)
+		s += fmt.Sprintf("%.1f%%, ", g.Share*100)
+		s += fmt.Sprintf("%s\n", topNames(g.Top))
+	}
+	return s
+}
+
+func GroupsToMD(by string, groups []*Group) string {
+
+	if by == "" {
+		by = "group"
+	}
+	s := "| " + strings.ToUpper(by[:1]) + by[1:] + " | Count | FIT | Share | Top contributors |\n"
+	s += "|---|---|---|---|---|\n"
+
+	for _, g := range groups {
+		s += fmt.Sprintf("| %s ", g.Name)
+		s += fmt.Sprintf("| %d ", g.Count)
+		s += fmt.Sprintf("| %.4f ", g.FIT)
+		s += fmt.Sprintf("| %.1f%% ", g.Share*100)
+		s += fmt.Sprintf("| %s |\n", topNames(g.Top))
+	}
+	return s
+}
+
+func topNames(cc []*Component) string {
+
+	var names []string
+	for _, c := range cc {
+		names = append(names, fmt.Sprintf("%s (%.4f)", strings.ToUpper(c.Name), c.FIT))
+	}
+	return strings.Join(names, " ")
+}
@@ -0,0 +1,90 @@
+package fides
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestRollup(t *testing.T) {
+
+	bom := &Bom{Components: []*Component{
+		{Name: "R1", Class: "R", Block: "psu", Tags: []string{"thick"}, FIT: 1},
+		{Name: "R2", Class: "R", Block: "cpu", Tags: []string{"thick", "shunt"}, FIT: 3},
+		{Name: "C1", Class: "C", Block: "psu", Tags: []string{"x7r"}, FIT: 4},
+		{Name: "U1", Class: "U", Block: "cpu", FIT: math.NaN()},
+	}}
+
+	type group struct {
+		name  string
+		count int
+		fit   float64
+		top   string
+	}
+
+	tests := []struct {
+		by   string
+		want []group
+	}{
+		{"block", []group{{"psu", 2, 5, "C1 R1"}, {"cpu", 2, 3, "R2"}}},
+		{"class", []group{{"C", 1, 4, "C1"}, {"R", 2, 4, "R2 R1"}, {"U", 1, 0, ""}}},
+		{"Tags", []group{{"thick", 2, 4, "R2 R1"}, {"x7r", 1, 4, "C1"}, {"shunt", 1, 3, "R2"}}},
+	}
+
+	for _, tt := range tests {
+
+		gg, err := bom.Rollup(tt.by, 2)
+		if err != nil {
+			t.Errorf("%s: %v", tt.by, err)
+			continue
+		}
+		if len(gg) != len(tt.want) {
+			t.Errorf("%s: %d groups, want %d", tt.by, len(gg), len(tt.want))
+			continue
+		}
+
+		for i, g := range gg {
+			var top []string
+			for _, c := range g.Top {
+				top = append(top, c.Name)
+			}
+			w := tt.want[i]
+			if g.Name != w.name || g.Count != w.count || g.FIT != w.fit || strings.Join(top, " ") != w.top {
+				t.Errorf("%s: group %d is %s, %d, %g, %v, want %+v", tt.by, i, g.Name, g.Count, g.FIT, top, w)
+			}
+			if !near(g.Share, w.fit/8) {
+				t.Errorf("%s: share of %s %g, want %g", tt.by, g.Name, g.Share, w.fit/8)
+			}
+		}
+	}
+
+	if _, err := bom.Rollup("package", 2); err == nil {
+		t.Error("no error for an unknown rollup field")
+	}
+}
+
+func TestGroupsToMD(t *testing.T) {
+
+	gg := []*Group{{Name: "psu", Count: 2, FIT: 5, Share: 0.625, Top: []*Component{{Name: "c1", FIT: 4}}}}
+
+	tests := []struct {
+		by, header string
+	}{
+		{"block", "| Block | Count"},
+		{"", "| Group | Count"},
+	}
+
+	for _, tt := range tests {
+		s := GroupsToMD(tt.by, gg)
+		if !strings.HasPrefix(s, tt.header) {
+			t.Errorf("GroupsToMD(%q): header %q", tt.by, strings.SplitN(s, "\n", 2)[0])
+		}
+		if !strings.Contains(s, "| psu | 2 | 5.0000 | 62.5% | C1 (4.0000) |") {
+			t.Errorf("GroupsToMD(%q): %q", tt.by, s)
+		}
+	}
+
+	if s := GroupsToCsv("", gg); !strings.HasPrefix(s, "group, count") {
+		t.Errorf("GroupsToCsv: %q", s)
+	}
+}
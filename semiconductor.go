@@ -2,69 +2,153 @@ package fides
 
 import (
 	"errors"
+	"fmt"
 	"math"
 )
 
 func SemiconductorFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(SemiconductorEval(comp, mission))
+}
 
-	vfactor := 1.0
-	if comp.Class == "D" && comp.Imax < 1 && !(contains(comp.Tags, "tvs") || contains(comp.Tags, "zener")) {
+// SemiconductorEval returns the detailed FIT of ICs, transistors and diodes.
+// The base lambdas are already in FIT, so the Base of the result is 1.
+func SemiconductorEval(comp *Component, mission *Mission) (*Result, error) {
 
-		if comp.Vmax == 0 || math.IsNaN(comp.Vmax) {
-			return math.NaN(), errors.New("Vmax not set")
-		}
+	// Voltage factor for signal diodes
+	vdep := comp.Class == "D" && comp.Imax < 1 && !(contains(comp.Tags, "tvs") || contains(comp.Tags, "zener"))
+	if vdep {
 
-		if comp.V == 0 || math.IsNaN(comp.V) {
-			return math.NaN(), errors.New("working V not set")
+		if comp.Vmax == 0 || math.IsNaN(comp.Vmax) {
+			return nil, errors.New("Vmax not set")
 		}
-
-		vfactor = PiThermal_voltageFactor(comp.V, comp.Vmax)
 	}
 
 	lth := Lchip_th(comp)
 	if lth < 0 {
-		return math.NaN(), errors.New("Missing data for lchip(th) calculation")
+		return nil, errors.New("Missing data for lchip(th) calculation")
 	}
 
 	p := NewPackage(comp.Package)
 	if p == nil {
-		return math.NaN(), errors.New("Package not found: [" + comp.Package + "]")
+		return nil, errors.New("Package not found: [" + comp.Package + "]")
 	}
 	comp.Np = p.Npins
 	lrh, ltc, lts, lm := p.FitBase()
 	if lrh < 0 || math.IsNaN(lrh) {
-		return math.NaN(), errors.New("Missing data for lpkg(rh,tc...) calculation for package: [" + p.Name + "]")
+		return nil, errors.New("Missing data for lpkg(rh,tc...) calculation for package: [" + p.Name + "]")
 	}
 
-	var factor float64
+	dsm := comp.Class == "U" && isDsm(comp)
 
-	// fmt.Printf("semi: lth %f, vfactor %f, lrh %f, ltc %f, lts %f, lm %f\n", lth, vfactor, lrh, ltc, lts, lm)
+	r := newResult(1)
+
+	if dsm && comp.Vcore > 1.1*dsmVnom(comp.Node) {
+		r.warn("Core voltage (%.2f V) more than 10%% above the nominal of the %.0f nm node (%.2f V)", comp.Vcore, comp.Node, dsmVnom(comp.Node))
+	}
 
 	for _, ph := range mission.Phases {
 
-		// TODO Add disipated power
-		tj := ph.Tamb
+		// Working conditions in this phase
+		c := comp.InPhase(ph)
+		on := mission.IsOn(comp, ph)
+		tamb := mission.Tamb(comp, ph)
 
-		pi := lth*PiThermal(0.7, tj, ph.On)*vfactor +
-			ltc*PiTCCase(ph.NCycles, ph.Duration, ph.Tdelta, ph.Tmax) +
-			lts*PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax) +
-			lrh*PiRH2(0.9, ph.RH, ph.Tamb, ph.On) +
-			lm*PiMech(ph.Grms)
+		vfactor := 1.0
+		if vdep {
+			if on && (c.V == 0 || math.IsNaN(c.V)) {
+				return nil, errors.New("working V not set")
+			}
+			vfactor = PiThermal_voltageFactor(c.V, comp.Vmax)
+		}
+
+		tj, err := phaseTj(c, p, tamb, on)
+		if err != nil {
+			return nil, err
+		}
 
 		// Proportion of time in this phase
-		pi *= ph.Duration / mission.Ttotal
+		w := ph.Duration / mission.Ttotal
+
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+		pi.Thermal = w * lth * PiThermal(0.7, tj, on) * vfactor
+		pi.TCycling = w * (ltc*PiTCCase(ph.NCycles, ph.Duration, ph.Tdelta, ph.Tmax) +
+			lts*PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax))
+		pi.Humidity = w * lrh * PiRH2(0.9, ph.RH, tamb, on)
+		pi.Mechanical = w * lm * PiMech(ph.Grms)
 
 		// Stress factors and sensibility
 		ifactor, err := PiInduced(comp, ph)
 		if err != nil {
-			return math.NaN(), err
+			return nil, err
+		}
+		pi.Induced = ifactor
+
+		// Wear-out of deep sub-micron ICs, only when powered
+		if dsm && on {
+			for i, fit := range dsmWearout(c, tj) {
+				pi.Wearout += w * fit
+				r.mechanism(dsmMechanisms[i], w*fit)
+			}
+		}
+
+		r.add(pi)
+	}
+
+	return r, nil
+}
+
+// Tjunction returns the junction temperature of a semiconductor that dissipates
+// comp.P at ambient temperature tamb. The thermal resistance used is, in order
+// of priority:
+//
+// - Rjc of the package + Rca, if a heatsink or case to ambient resistance is given
+// - Rtha, if given in the BOM
+// - the junction to ambient resistance of the package (low conductivity substrate)
+func Tjunction(comp *Component, p *Package, tamb float64) (float64, error) {
+
+	if comp.P == 0 || math.IsNaN(comp.P) {
+		return tamb, nil
+	}
+
+	var rth float64
+
+	if comp.Rca > 0 {
+		rjc := p.Rjc()
+		if math.IsNaN(rjc) {
+			return math.NaN(), errors.New("Rjc not known for package: [" + p.Name + "]")
+		}
+		rth = rjc + comp.Rca
+	} else if comp.Rtha > 0 {
+		rth = comp.Rtha
+	} else {
+		rth = p.Rtha(0)
+		if rth <= 0 || math.IsNaN(rth) {
+			return math.NaN(), errors.New("Rth could not be set for this package: [" + p.Name + "]")
 		}
-		pi *= ifactor
+	}
+
+	return tamb + comp.P*rth, nil
+}
+
+// phaseTj returns the junction temperature in a phase (the local ambient
+// temperature if the component is off), checking it against Tmax.
+func phaseTj(comp *Component, p *Package, tamb float64, on bool) (float64, error) {
+
+	if !on {
+		return tamb, nil
+	}
+
+	tj, err := Tjunction(comp, p, tamb)
+	if err != nil {
+		return math.NaN(), err
+	}
 
-		factor += pi
+	if tj >= comp.Tmax {
+		s := fmt.Sprintf("Junction temperature (%f ºC) exceeds its Tmax (%f ºC), P=%f W", tj, comp.Tmax, comp.P)
+		return math.NaN(), errors.New(s)
 	}
 
-	return factor * PiPM() * PiProcess(), nil
+	return tj, nil
 }
 
 func Lchip_th(c *Component) float64 {
@@ -0,0 +1,105 @@
+package fides
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTjunction(t *testing.T) {
+
+	p := NewPackage("TO220")
+
+	tests := []struct {
+		name string
+		comp *Component
+		want float64
+	}{
+		{"no power", &Component{P: 0}, 40},
+		{"rtha", &Component{P: 0.5, Rtha: 100}, 90},
+		{"rca", &Component{P: 2, Rca: 6, Rtha: 100}, 40 + 2*(4+6)},
+		{"package", &Component{P: 0.5}, 40 + 0.5*p.Rtha(0)},
+	}
+
+	for _, tt := range tests {
+		got, err := Tjunction(tt.comp, p, 40)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !near(got, tt.want) {
+			t.Errorf("%s: Tj = %g, want %g", tt.name, got, tt.want)
+		}
+	}
+
+	// Rca needs the Rjc of the package
+	if _, err := Tjunction(&Component{P: 1, Rca: 5}, NewPackage("DPAK"), 40); err == nil {
+		t.Error("no error for Rca without Rjc")
+	}
+}
+
+func TestPhaseTj(t *testing.T) {
+
+	p := NewPackage("SOT23")
+
+	tests := []struct {
+		name string
+		on   bool
+		tmax float64
+		want float64
+		err  bool
+	}{
+		{"off", false, 150, 40, false},
+		{"on", true, 150, 140, false},
+		{"off above tmax", false, 125, 40, false},
+		{"on above tmax", true, 125, 0, true},
+	}
+
+	for _, tt := range tests {
+		c := &Component{P: 0.5, Rtha: 200, Tmax: tt.tmax}
+		tj, err := phaseTj(c, p, 40, tt.on)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%s: no error, Tj = %g", tt.name, tj)
+			}
+			continue
+		}
+		if err != nil || !near(tj, tt.want) {
+			t.Errorf("%s: Tj = %g, %v, want %g", tt.name, tj, err, tt.want)
+		}
+	}
+}
+
+func TestSemiconductorEval(t *testing.T) {
+
+	m := testMission(40)
+
+	fit := func(p float64) float64 {
+		c := &Component{Class: "Q", Package: "SOT23", Tmax: 150, Pmax: 0.3, P: p}
+		r, err := SemiconductorEval(c, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r.FIT
+	}
+	if fit(0.2) <= fit(0) {
+		t.Error("the FIT does not increase with the dissipated power")
+	}
+
+	tests := []struct {
+		name string
+		comp *Component
+		err  string
+	}{
+		{"above tmax", &Component{Class: "Q", Package: "SOT23", Tmax: 150, Pmax: 0.3, P: 0.3, Rtha: 500}, "exceeds its Tmax"},
+		{"unknown package", &Component{Class: "U", Package: "XYZ12", Tmax: 125}, "package"},
+		{"signal diode without vmax", &Component{Class: "D", Package: "SOD123", Tmax: 150, V: 5}, "Vmax"},
+		{"signal diode without v", &Component{Class: "D", Package: "SOD123", Tmax: 150, Vmax: 75}, "working V"},
+	}
+
+	for _, tt := range tests {
+		_, err := SemiconductorEval(tt.comp, m)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package fides
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SwitchFIT returns the FIT of a switch, pushbutton or rotary encoder (class SW)
+func SwitchFIT(comp *Component, mission *Mission) (float64, error) {
+	return fitOf(SwitchEval(comp, mission))
+}
+
+// SwitchEval returns the detailed FIT of a switch. The contacts are exposed to
+// the environment as those of connectors, and wear out with the actuations
+// (Ops per hour, or per phase in the loads file). Cycles is the rated
+// mechanical life; if not given, a typical value of the switch type is used.
+func SwitchEval(comp *Component, mission *Mission) (*Result, error) {
+
+	stype := switchType(comp.Tags)
+	fit, life := lbase_switch(stype)
+
+	if comp.Cycles > 0 {
+		life = comp.Cycles
+	}
+
+	sealed := contains(comp.Tags, "sealed")
+
+	r := newResult(fit)
+
+	for _, ph := range mission.Phases {
+
+		// Working conditions in this phase
+		c := comp.InPhase(ph)
+		on := mission.IsOn(comp, ph)
+		tamb := mission.Tamb(comp, ph)
+
+		if tamb+c.T > comp.Tmax {
+			s := fmt.Sprintf("Using component above its Tmax %f (Tamb=%f, Td=%f)", comp.Tmax, tamb, c.T)
+			return nil, errors.New(s)
+		}
+		if comp.Imax > 0 && c.I > comp.Imax {
+			s := fmt.Sprintf("Contact current (%f A) exceeds its Imax (%f A)", c.I, comp.Imax)
+			return nil, errors.New(s)
+		}
+
+		// Proportion of time in this phase
+		w := ph.Duration / mission.Ttotal
+
+		pi := &Contribution{Phase: ph.Name, Weight: w}
+
+		pi.Thermal = w * 0.58 * PiThermal(0.1, tamb+c.T, on)
+		pi.TCycling = w * 0.04 * PiTCSolder(ph.NCycles, ph.Duration, ph.CycleDuration, ph.Tdelta, ph.Tmax)
+
+		// Mechanical, humidity and chemical, as for connectors
+		contactTerms(pi, w, ph, tamb, ph.IP || sealed)
+
+		// Stress factors and sensibility
+		ifactor, err := PiInduced(comp, ph)
+		if err != nil {
+			return nil, err
+		}
+		pi.Induced = ifactor
+
+		// Actuations wear out the switch also when the circuit is off
+		pi.Wearout = w * opsWearout(c.Ops, life)
+
+		r.add(pi)
+	}
+
+	return r, nil
+}
+
+// switchType returns tactile (pushbuttons, default), toggle (toggle, slide and
+// rocker switches), dip or encoder (rotary switches and encoders)
+func switchType(tags []string) string {
+
+	for _, tag := range tags {
+		switch tag {
+		case "toggle", "slide", "rocker":
+			return "toggle"
+		case "dip":
+			return "dip"
+		case "rotary", "encoder":
+			return "encoder"
+		}
+	}
+	return "tactile"
+}
+
+// Returns l0 and the rated life in actuations
+func lbase_switch(stype string) (float64, float64) {
+
+	switch stype {
+	case "toggle":
+		return 0.8, 3e4
+	case "dip":
+		// Set once, rarely changed
+		return 0.3, 1e3
+	case "encoder":
+		return 1.0, 3e4
+	}
+	return 0.5, 1e5
+}
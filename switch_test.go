@@ -0,0 +1,62 @@
+package fides
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSwitchEval(t *testing.T) {
+
+	m := testMission(40)
+
+	tests := []struct {
+		name    string
+		comp    *Component
+		wearout float64
+	}{
+		{"tactile", &Component{Class: "SW", Tmax: 85, Ops: 1}, 1000},
+		{"toggle", &Component{Class: "SW", Tags: []string{"rocker"}, Tmax: 85, Ops: 0.3}, 1000},
+		{"dip", &Component{Class: "SW", Tags: []string{"dip"}, Tmax: 85, Ops: 0.001}, 100},
+		{"encoder", &Component{Class: "SW", Tags: []string{"encoder"}, Tmax: 85, Ops: 3}, 1e4},
+		{"rated life", &Component{Class: "SW", Tmax: 85, Ops: 1, Cycles: 1e6}, 100},
+	}
+
+	for _, tt := range tests {
+		r, err := SwitchEval(tt.comp, m)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !near(r.Wearout(), tt.wearout) {
+			t.Errorf("%s: wear-out %g, want %g", tt.name, r.Wearout(), tt.wearout)
+		}
+	}
+
+	// Actuated while the equipment is off
+	m.Phases[0].On = false
+	r, err := SwitchEval(&Component{Class: "SW", Tmax: 85, Ops: 1}, m)
+	if err != nil || !near(r.Wearout(), 1000) {
+		t.Errorf("off: wear-out %v, %v, want 1000", r, err)
+	}
+}
+
+func TestSwitchEvalErrors(t *testing.T) {
+
+	m := testMission(40)
+
+	tests := []struct {
+		name string
+		comp *Component
+		err  string
+	}{
+		{"above imax", &Component{Class: "SW", Tmax: 85, Imax: 0.05, I: 0.1}, "Imax"},
+		{"above tmax", &Component{Class: "SW", Tmax: 50, T: 15}, "Tmax"},
+	}
+
+	for _, tt := range tests {
+		_, err := SwitchEval(tt.comp, m)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package fides
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Units and the ways they can be written (lower case)
+var units = map[string][]string{
+	"V":      {"v", "volt", "volts"},
+	"W":      {"w", "watt", "watts"},
+	"A":      {"a", "amp", "amps"},
+	"ºC":     {"ºc", "°c", "degc", "c"},
+	"ºC/W":   {"ºc/w", "°c/w", "c/w", "k/w"},
+	"ppm/ºC": {"ppm/ºc", "ppm/°c", "ppm/c", "ppm/k", "ppm"},
+	"h":      {"h", "hour", "hours"},
+	"s":      {"s", "sec"},
+	"g":      {"g", "grms"},
+	"%":      {"%"},
+	"Hz":     {"hz"},
+	"F":      {"f"},
+	"Ω":      {"Ω", "ω", "ohm", "ohms"},
+	"J":      {"j", "joule", "joules"},
+}
+
+// Unit aliases sorted by decreasing length, so that c/w is matched before w
+var unitAliases []string
+var aliasUnit = make(map[string]string)
+
+func init() {
+	for unit, aa := range units {
+		for _, a := range aa {
+			unitAliases = append(unitAliases, a)
+			aliasUnit[a] = unit
+		}
+	}
+	sort.Slice(unitAliases, func(i, j int) bool {
+		if len(unitAliases[i]) == len(unitAliases[j]) {
+			return unitAliases[i] < unitAliases[j]
+		}
+		return len(unitAliases[i]) > len(unitAliases[j])
+	})
+}
+
+var siPrefixes = map[string]float64{
+	"p": 1e-12, "n": 1e-9, "u": 1e-6, "µ": 1e-6, "m": 1e-3,
+	"k": 1e3, "K": 1e3, "M": 1e6, "meg": 1e6, "G": 1e9, "g": 1e9,
+}
+
+var reNumber = regexp.MustCompile(`^([+-]?(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][+-]?[0-9]+)?)(.*)$`)
+
+// ParseUnit parses a numeric value that is expected to be in the given unit
+// (V, W, A, ºC, ºC/W, h, g, %, or "" for dimensionless values). It accepts:
+//
+//   - SI prefixes: 100mW, 2.2k, 1M or 1meg, 2.4G
+//   - the unit or prefix as decimal point: 3V3, 4k7
+//   - fractions: 1/8W
+//   - percentages (for dimensionless values): 5%
+//   - temperatures written as 125C, 125ºC or 125°C
+//
+// A value in another unit (5A in a voltage field) is an error. The input files
+// are read in lower case, so m (milli) and M (mega) can not be told apart in
+// them: frequencies in mhz are MHz, and a lowercase m is rejected as
+// ambiguous in other frequencies and in resistances (write meg or M).
+func ParseUnit(s, unit string) (float64, error) {
+
+	t := strings.ReplaceAll(strings.TrimSpace(s), " ", "")
+	if strings.HasSuffix(t, "%") && unit == "" {
+		f, err := parseFraction(t[:len(t)-1])
+		return f / 100, err
+	}
+	if t == "" {
+		return math.NaN(), errors.New("empty value")
+	}
+
+	// Unit at the end
+	lt := strings.ToLower(t)
+	for _, a := range unitAliases {
+		if !strings.HasSuffix(lt, a) || len(a) == len(lt) || strings.HasSuffix(lt, "meg") {
+			continue
+		}
+		// g is giga in frequencies (2.4g)
+		if a == "g" && unit == "Hz" {
+			continue
+		}
+		u := aliasUnit[a]
+		if u != unit {
+			return math.NaN(), errors.New("unit " + u + " given, expected " + expected(unit))
+		}
+		t = t[:len(t)-len(a)]
+		if u == "Hz" && strings.HasSuffix(t, "m") {
+			t = t[:len(t)-1] + "M"
+		}
+		break
+	}
+
+	if (unit == "Hz" || unit == "Ω") && ambiguousMilli(t) {
+		return math.NaN(), errors.New("ambiguous prefix m (milli or mega), write meg or M for mega")
+	}
+
+	// Unit as decimal point (3V3)
+	lt = strings.ToLower(t)
+	for _, a := range units[unit] {
+		if i := strings.Index(lt, a); i > 0 && i+len(a) < len(lt) && isDigit(lt[i-1]) && isDigit(lt[i+len(a)]) {
+			t = t[:i] + "." + t[i+len(a):]
+			break
+		}
+	}
+
+	f, err := parseFraction(t)
+	if err != nil {
+		return math.NaN(), errors.New(err.Error() + ", expected " + expected(unit))
+	}
+	return f, nil
+}
+
+func expected(unit string) string {
+	if unit == "" {
+		return "a number"
+	}
+	return "a number in " + unit
+}
+
+func parseFraction(s string) (float64, error) {
+
+	i := strings.Index(s, "/")
+	if i < 0 {
+		return parseNumber(s)
+	}
+
+	a, err := parseNumber(s[:i])
+	if err != nil {
+		return math.NaN(), err
+	}
+	b, err := parseNumber(s[i+1:])
+	if err != nil {
+		return math.NaN(), err
+	}
+	if b == 0 {
+		return math.NaN(), errors.New("division by zero")
+	}
+	return a / b, nil
+}
+
+// parseNumber parses a number with an optional SI prefix, also used as
+// decimal point (4k7)
+func parseNumber(s string) (float64, error) {
+
+	m := reNumber.FindStringSubmatch(s)
+	if m == nil {
+		return math.NaN(), errors.New("not a number")
+	}
+
+	f, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return math.NaN(), err
+	}
+
+	rest := m[2]
+	if rest == "" {
+		return f, nil
+	}
+
+	for _, p := range []string{"meg", "p", "n", "u", "µ", "m", "k", "K", "M", "G", "g"} {
+		if !strings.HasPrefix(rest, p) {
+			continue
+		}
+		digits := rest[len(p):]
+		if digits != "" {
+			if strings.Contains(m[1], ".") || !allDigits(digits) {
+				return math.NaN(), errors.New("not a number")
+			}
+			f, _ = strconv.ParseFloat(m[1]+"."+digits, 64)
+		}
+		return f * siPrefixes[p], nil
+	}
+
+	return math.NaN(), errors.New("not a number")
+}
+
+// ambiguousMilli returns true if s has a lowercase m used as prefix (not meg)
+func ambiguousMilli(s string) bool {
+	i := strings.Index(s, "m")
+	return i >= 0 && !strings.HasPrefix(s[i:], "meg")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseField parses a field of an input file in the given unit. Empty fields
+// return def. Errors are added to errs, with the name of the item and field.
+func parseField(errs *[]error, name, key, val, unit string, def float64) float64 {
+
+	if val == "" {
+		return def
+	}
+
+	f, err := ParseUnit(val, unit)
+	if err != nil {
+		*errs = append(*errs, errors.New(name+": "+key+": "+err.Error()+" ["+val+"]"))
+		return def
+	}
+	return f
+}
+
+// joinErrors returns the errors sorted, as one error (nil if none)
+func joinErrors(errs []error) error {
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errors.Join(errs...)
+}
@@ -0,0 +1,85 @@
+package fides
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseUnit(t *testing.T) {
+
+	tests := []struct {
+		s, unit string
+		want    float64
+	}{
+		{"5", "V", 5},
+		{"3V3", "V", 3.3},
+		{"3v3", "V", 3.3},
+		{"100mW", "W", 0.1},
+		{"100mw", "W", 0.1},
+		{"1/8W", "W", 0.125},
+		{"1/8", "W", 0.125},
+		{"4k7", "", 4700},
+		{"2.2k", "", 2200},
+		{"1meg", "", 1e6},
+		{"1M", "", 1e6},
+		{"5%", "", 0.05},
+		{"125C", "ºC", 125},
+		{"125ºC", "ºC", 125},
+		{"125°c", "ºC", 125},
+		{"-40", "ºC", -40},
+		{"10 k/w", "ºC/W", 10},
+		{"1e-3", "A", 1e-3},
+		{"10MHz", "Hz", 10e6},
+		{"10mhz", "Hz", 10e6},
+		{"2.4GHz", "Hz", 2.4e9},
+		{"2.4ghz", "Hz", 2.4e9},
+		{"2.4g", "Hz", 2.4e9},
+		{"32.768khz", "Hz", 32768},
+		{"1M", "Ω", 1e6},
+		{"1meg", "Ω", 1e6},
+		{"1megohm", "Ω", 1e6},
+		{"0.01ohm", "Ω", 0.01},
+		{"10u", "Ω", 10e-6},
+		{"5g", "g", 5},
+		{"100ms", "s", 0.1},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseUnit(tt.s, tt.unit)
+		if err != nil {
+			t.Errorf("ParseUnit(%q, %q): %v", tt.s, tt.unit, err)
+			continue
+		}
+		if !near(got, tt.want) {
+			t.Errorf("ParseUnit(%q, %q) = %g, want %g", tt.s, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestParseUnitErrors(t *testing.T) {
+
+	tests := []struct {
+		s, unit string
+	}{
+		{"", "V"},
+		{"abc", "V"},
+		{"5A", "V"},
+		{"2g", "W"},
+		{"1/0", ""},
+		{"4.1k7", ""},
+		{"1m", "Ω"},
+		{"10mohm", "Ω"},
+		{"4m7", "Ω"},
+		{"10m", "Hz"},
+		{"5%", "V"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseUnit(tt.s, tt.unit)
+		if err == nil {
+			t.Errorf("ParseUnit(%q, %q) = %g, want an error", tt.s, tt.unit, got)
+		} else if !math.IsNaN(got) {
+			t.Errorf("ParseUnit(%q, %q) = %g with error, want NaN", tt.s, tt.unit, got)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package fides
 
 import (
 	"encoding/csv"
+	"io"
 	"os"
 	"strings"
 )
@@ -18,17 +19,38 @@ func contains(tags []string, tag string) bool {
 
 // Read a CVS file into and array of maps
 func csvRead(file string) ([]map[string]string, error) {
+	rr, _, err := csvReadLines(file)
+	return rr, err
+}
+
+// csvReadLines is like csvRead, but also returns the line number in the file
+// of each record, for diagnostics.
+func csvReadLines(file string) ([]map[string]string, []int, error) {
 
 	f, err := os.Open(file)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer f.Close()
 
 	r := csv.NewReader(f)
-	m, err := r.ReadAll()
-	if err != nil {
-		return nil, err
+
+	var m [][]string
+	var lines []int
+	for {
+		l, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := r.FieldPos(0)
+		m = append(m, l)
+		lines = append(lines, line)
+	}
+	if len(m) == 0 {
+		return nil, nil, nil
 	}
 
 	// The first line contains the field names or keys
@@ -56,5 +78,5 @@ func csvRead(file string) ([]map[string]string, error) {
 		rr = append(rr, r)
 	}
 
-	return rr, nil
+	return rr, lines[1:], nil
 }
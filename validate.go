@@ -0,0 +1,423 @@
+package fides
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rveen/electronics"
+)
+
+// Diagnostic is a problem found in the BOM
+type Diagnostic struct {
+	File   string // Empty if the problem is in the merged BOM
+	Row    int    // Line in the file, 0 if not known
+	Column string
+	Value  string
+	Name   string // Component reference (or type in db files)
+	Msg    string
+}
+
+func (d *Diagnostic) String() string {
+
+	s := ""
+	if d.File != "" {
+		s = fmt.Sprintf("%s:%d: ", d.File, d.Row)
+	}
+	if d.Name != "" {
+		s += strings.ToUpper(d.Name) + ": "
+	}
+	if d.Column != "" {
+		s += d.Column + ": "
+	}
+	s += d.Msg
+	if d.Value != "" {
+		s += " [" + d.Value + "]"
+	}
+	return s
+}
+
+// Numeric fields of BOM files and their units
+var bomUnits = map[string]string{
+	"ndevices":   "",
+	"npins":      "",
+	"vmax":       "V",
+	"v":          "V",
+	"vpmax":      "V",
+	"vp":         "V",
+	"pmax":       "W",
+	"p":          "W",
+	"imax":       "A",
+	"i":          "A",
+	"tmax":       "ºC",
+	"tmin":       "ºC",
+	"t":          "ºC",
+	"rtha":       "ºC/W",
+	"rca":        "ºC/W",
+	"tc":         "ppm/ºC",
+	"ops":        "",
+	"duty":       "",
+	"cycles":     "",
+	"ripple":     "A",
+	"esr":        "Ω",
+	"life":       "h",
+	"tref":       "ºC",
+	"speed":      "",
+	"ip":         "A",
+	"tp":         "s",
+	"node":       "",
+	"vcore":      "V",
+	"activity":   "",
+	"prf":        "W",
+	"prfmax":     "W",
+	"freq":       "Hz",
+	"surge":      "J",
+	"surges":     "",
+	"emax":       "J",
+	"vwithstand": "V",
+}
+
+// Classes handled by FIT
+var knownClasses = []string{"U", "Q", "D", "R", "C", "L", "J", "X", "F", "RL", "SW", "RV", "GDT", "BT", "FAN", "RF"}
+
+// Tags recognized by the models, per class. Tags in css (induced.go) are also
+// accepted.
+var knownTags = map[string][]string{
+	"": {"smd", "tht", "analog", "interface", "power"},
+	"C": {"tant", "tantalium", "alu", "elco", "dry", "solid", "wet", "glass_sealed", "silver_case", "axial",
+		"cer", "flex", "type1", "type2", "topend", "x5r", "x5s", "x6r", "x6s", "x7r", "x7s", "x8r", "x8s", "np0", "c0g", "y5v",
+		"film", "pp", "pet", "pps", "mkp", "mkt", "x1", "x2", "y1", "y2", "supercap", "edlc", "edge", "connector"},
+	"R": {"melf", "thick", "thin", "ww", "potmeter", "network", "chip", "cartridge", "pptc", "resettable", "polyfuse",
+		"ntc", "ptc", "kty", "shunt", "sense"},
+	"L": {"trafo", "multilayer", "ferrite_bead"},
+	"D": {"zener", "tvs", "esd", "array", "led", "ingan", "algainp", "ir", "infrared", "white", "blue", "green", "red", "orange", "amber", "yellow"},
+	"Q": {"gan", "gaas", "igbt", "triac", "thyristor", "jfet", "mos", "mosfet"},
+	"U": {"opto", "optocoupler", "photodiode", "mixed", "fpga", "cpld", "pal", "microprocessor", "microcontroller",
+		"dsp", "complex", "flash", "eprom", "eeprom", "sram", "dram", "digital", "tvs", "esd",
+		"hybrid", "sip", "mcm", "ceramic", "laminate", "organic", "silicon", "interposer", "hermetic", "molded", "glob"},
+	"RV":  {"mov", "varistor"},
+	"GDT": {"gdt"},
+	"FAN": {"ball", "sleeve", "fluid", "fdb", "hydro"},
+	"RF":  {"mmic", "pa", "ldmos", "gaas", "gan", "sige", "cmos", "saw", "baw", "circulator", "isolator", "connector", "coax", "attenuator"},
+	"BT":  {"coin", "limno2", "liion", "li-ion", "lipo", "lifepo4", "lfp", "nimh"},
+	"X":   {"osc", "oscillator"},
+	"J":   {"pressfit"},
+	"SW":  {"tactile", "pushbutton", "toggle", "slide", "rocker", "dip", "rotary", "encoder", "sealed"},
+	"RL":  {"signal", "sealed", "resistive", "inductive", "motor", "lamp", "capacitive"},
+	"F":   {"fuse", "chip", "cartridge", "ptc", "pptc", "resettable", "polyfuse"},
+}
+
+// ValidateCsvs checks the BOM files (as given to Bom.FromCsvs) and returns the
+// problems found: numeric fields that cannot be parsed, and the problems
+// reported by Bom.Validate, with the file and line they come from.
+func ValidateCsvs(files []string) ([]*Diagnostic, error) {
+
+	var dd []*Diagnostic
+	var rows []*bomRow
+
+	for _, file := range files {
+
+		m, lines, err := csvReadLines(file)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, r := range m {
+
+			rows = append(rows, &bomRow{file: file, line: lines[i], fields: r})
+
+			var cols []string
+			for col := range r {
+				cols = append(cols, col)
+			}
+			sort.Strings(cols)
+
+			for _, col := range cols {
+				if d := checkField(col, r[col]); d != nil {
+					d.File = file
+					d.Row = lines[i]
+					d.Name = r["name"]
+					dd = append(dd, d)
+				}
+			}
+		}
+	}
+
+	bom := &Bom{}
+	bom.FromCsvs(files)
+
+	for _, c := range bom.Components {
+		for _, d := range c.Validate() {
+			locate(d, c, rows)
+			dd = append(dd, d)
+		}
+	}
+	return dd, nil
+}
+
+// bomRow is a record of a BOM file, with its position
+type bomRow struct {
+	file   string
+	line   int
+	fields map[string]string
+}
+
+// locate sets the file and line of a problem of a component of the merged
+// BOM: the row of the component (by name) or of its type that gives the
+// column, else the row of the component.
+func locate(d *Diagnostic, c *Component, rows []*bomRow) {
+
+	var first *bomRow
+
+	for _, r := range rows {
+
+		// Rows of components by name, rows of other files by type
+		name := r.fields["name"]
+		if name != "" && !strings.EqualFold(name, c.Name) {
+			continue
+		}
+		if name == "" && (c.Type == "" || !strings.EqualFold(r.fields["type"], c.Type)) {
+			continue
+		}
+		if first == nil && name != "" {
+			first = r
+		}
+		if d.Column != "" && r.fields[d.Column] != "" {
+			d.File, d.Row = r.file, r.line
+			return
+		}
+	}
+
+	if first != nil {
+		d.File, d.Row = first.file, first.line
+	}
+}
+
+func checkField(col, val string) *Diagnostic {
+
+	unit, ok := bomUnits[col]
+	if !ok || val == "" {
+		return nil
+	}
+
+	if col == "ndevices" || col == "npins" {
+		if _, err := strconv.Atoi(val); err != nil {
+			return &Diagnostic{Column: col, Value: val, Msg: "expected an integer"}
+		}
+		return nil
+	}
+
+	if _, err := ParseUnit(val, unit); err != nil {
+		return &Diagnostic{Column: col, Value: val, Msg: err.Error()}
+	}
+	return nil
+}
+
+// Validate checks the components of the BOM for unknown classes, tags and
+// packages, and for missing mandatory fields. The diagnostics have no file
+// and line; ValidateCsvs adds them.
+func (bom *Bom) Validate() []*Diagnostic {
+
+	var dd []*Diagnostic
+
+	for _, c := range bom.Components {
+		dd = append(dd, c.Validate()...)
+	}
+	return dd
+}
+
+// Validate checks a component for unknown class, tags and package, for
+// missing mandatory fields, and for working conditions above the limits of
+// the component (v, i, p) that would fail the evaluation.
+func (c *Component) Validate() []*Diagnostic {
+
+	var dd []*Diagnostic
+
+	add := func(col, val, msg string) {
+		dd = append(dd, &Diagnostic{Name: c.Name, Column: col, Value: val, Msg: msg})
+	}
+	missing := func(f float64) bool {
+		return f == 0 || math.IsNaN(f)
+	}
+
+	class := strings.ToUpper(c.Class)
+
+	if !contains(knownClasses, class) {
+		add("class", c.Class, "unknown class")
+		return dd
+	}
+
+	for _, tag := range c.Tags {
+		if !knownTag(class, tag) {
+			add("tags", tag, "unknown tag for class "+class)
+		}
+	}
+
+	if missing(c.Tmax) {
+		add("tmax", "", "missing")
+	}
+
+	// Working conditions given in the BOM against the limits
+	above := func(col string, v, max float64) {
+		if !missing(v) && !missing(max) && v > max {
+			add(col, fmt.Sprintf("%g > %g", v, max), "above "+col+"max")
+		}
+	}
+	above("v", c.V, c.Vmax)
+	above("i", c.I, c.Imax)
+	above("p", c.P, c.Pmax)
+
+	switch class {
+
+	case "C":
+		if missing(c.Vmax) {
+			add("vmax", "", "missing")
+		}
+		if missing(c.V) {
+			add("v", "", "missing")
+		}
+		switch capType(c.Tags) {
+		case "":
+			add("tags", strings.Join(c.Tags, " "), "capacitor type (dielectric) not given")
+		case "cer":
+			// The case size gives the flex-crack sensitivity
+			if c.Package == "" {
+				add("package", "", "missing (case size)")
+			} else if mlccSize(c.Package) == "" {
+				add("package", c.Package, "unknown case size (taken as 0805)")
+			}
+		case "tant", "alu":
+			if c.Package == "" && !contains(c.Tags, "smd") && !contains(c.Tags, "tht") {
+				add("package", "", "missing (taken as smd)")
+			}
+		}
+
+	case "R", "F":
+		if class == "F" || contains(c.Tags, "fuse") {
+			if missing(c.Imax) {
+				add("imax", "", "missing (rated current)")
+			}
+			if missing(c.I) {
+				add("i", "", "missing")
+			}
+			break
+		}
+		if missing(c.Pmax) {
+			add("pmax", "", "missing")
+		}
+		if missing(c.P) && missing(c.V) && missing(c.I) && resistorVariant(c.Tags) != "ntc" && resistorVariant(c.Tags) != "ptc" {
+			add("p", "", "missing (or v or i)")
+		}
+		if c.Package == "" {
+			add("package", "", "missing")
+		} else if missing(c.Rtha) && electronics.Rth(c.Package) == 0 {
+			add("package", c.Package, "unknown package (no thermal resistance, give rtha)")
+		}
+
+	case "RL":
+		if missing(c.Imax) && !contains(c.Tags, "power") && !contains(c.Tags, "signal") {
+			add("imax", "", "missing (contact rating, or tag power or signal)")
+		}
+		if c.Duty < 0 || c.Duty > 1 {
+			add("duty", fmt.Sprint(c.Duty), "coil duty outside 0..1")
+		}
+
+	case "SW":
+		if !missing(c.I) && missing(c.Imax) {
+			add("imax", "", "missing (contact rating)")
+		}
+
+	case "L":
+		if c.Package == "" {
+			add("package", "", "missing")
+		}
+
+	case "X":
+		// Only tmax and the working conditions above are needed
+
+	case "RV", "GDT":
+		if missing(c.Vmax) {
+			add("vmax", "", "missing (standoff voltage)")
+		}
+
+	case "U", "Q", "D":
+		if class == "U" && isHybrid(c) {
+			if _, err := parseDies(c.Dies); err != nil {
+				add("dies", c.Dies, err.Error())
+			} else if strings.TrimSpace(c.Dies) == "" {
+				add("dies", "", "missing")
+			}
+		}
+		if isProtection(c) {
+			break
+		}
+		if class == "D" && contains(c.Tags, "led") {
+			// LED packages default to small signal packages
+			if missing(c.I) {
+				add("i", "", "missing (forward current)")
+			}
+			break
+		}
+		if c.Package == "" {
+			add("package", "", "missing")
+		} else if !knownPackage(c.Package) {
+			add("package", c.Package, "unknown package")
+		}
+		if class == "D" && c.Imax < 1 && !(contains(c.Tags, "tvs") || contains(c.Tags, "zener")) {
+			if missing(c.Vmax) {
+				add("vmax", "", "missing")
+			}
+			if missing(c.V) {
+				add("v", "", "missing")
+			}
+		}
+
+	case "RF":
+		switch rfType(c.Tags) {
+		case "mmic", "pa":
+			if c.Package == "" {
+				add("package", "", "missing")
+			} else if !knownPackage(c.Package) {
+				add("package", c.Package, "unknown package")
+			}
+		case "attenuator":
+			if missing(c.Value) {
+				add("value", "", "missing (attenuation in dB)")
+			}
+		}
+
+	case "J":
+		if c.Np < 1 {
+			add("npins", "", "missing")
+		}
+	}
+
+	return dd
+}
+
+func knownTag(class, tag string) bool {
+
+	if contains(knownTags[""], tag) || contains(knownTags[class], tag) {
+		return true
+	}
+
+	for _, cref := range css {
+		if cref.class == class && contains(strings.Fields(cref.tags), tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// knownPackage returns true if there is FIT data for the package
+func knownPackage(name string) bool {
+
+	if packages[name] != nil {
+		return true
+	}
+	s, n := splitPkg(name)
+	rh, _, _, _ := lbase_case(s, n)
+	return rh >= 0
+}
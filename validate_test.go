@@ -0,0 +1,87 @@
+package fides
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComponentValidate(t *testing.T) {
+
+	tests := []struct {
+		name string
+		comp *Component
+		want []string // column: message, empty if valid
+	}{
+		{"valid mlcc", &Component{Class: "C", Tags: []string{"x7r"}, Package: "0805", Tmax: 125, Vmax: 50, V: 5}, nil},
+		{"metric mlcc", &Component{Class: "C", Tags: []string{"x7r"}, Package: "C2012", Tmax: 125, Vmax: 50, V: 5}, nil},
+		{"mlcc without package", &Component{Class: "C", Tags: []string{"x7r"}, Tmax: 125, Vmax: 50, V: 5}, []string{"package: missing"}},
+		{"mlcc unknown size", &Component{Class: "C", Tags: []string{"x7r"}, Package: "SMD", Tmax: 125, Vmax: 50, V: 5}, []string{"package: unknown case size"}},
+		{"tantalum without package", &Component{Class: "C", Tags: []string{"tant"}, Tmax: 125, Vmax: 16, V: 5}, []string{"package: missing"}},
+		{"tantalum tagged smd", &Component{Class: "C", Tags: []string{"tant", "smd"}, Tmax: 125, Vmax: 16, V: 5}, nil},
+		{"film", &Component{Class: "C", Tags: []string{"pp"}, Tmax: 105, Vmax: 400, V: 230}, nil},
+		{"inductor", &Component{Class: "L", Tags: []string{"power"}, Package: "1210", Tmax: 125}, nil},
+		{"inductor without package", &Component{Class: "L", Tags: []string{"power"}, Tmax: 125}, []string{"package: missing"}},
+		{"unknown class", &Component{Class: "ZZ", Tmax: 125}, []string{"class: unknown class"}},
+		{"above vmax", &Component{Class: "C", Tags: []string{"x7r"}, Package: "0805", Tmax: 125, Vmax: 50, V: 60}, []string{"v: above vmax"}},
+		{"tvs", &Component{Class: "D", Tags: []string{"tvs"}, Tmax: 150, Vmax: 5}, nil},
+		{"tvs without vmax", &Component{Class: "D", Tags: []string{"tvs"}, Tmax: 150}, []string{"package: missing"}},
+	}
+
+	for _, tt := range tests {
+
+		dd := tt.comp.Validate()
+
+		var got []string
+		for _, d := range dd {
+			got = append(got, d.Column+": "+d.Msg)
+		}
+
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if !strings.HasPrefix(got[i], tt.want[i]) {
+				t.Errorf("%s: %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestLocate(t *testing.T) {
+
+	rows := []*bomRow{
+		{"bom.csv", 2, map[string]string{"name": "c1", "type": "cap100n", "class": "c", "v": "5"}},
+		{"bom.csv", 3, map[string]string{"name": "c2", "type": "cap100n", "v": "12"}},
+		{"db.csv", 5, map[string]string{"type": "cap100n", "package": "0805", "vmax": "16"}},
+	}
+
+	c := &Component{Name: "C2", Type: "cap100n"}
+
+	tests := []struct {
+		column string
+		file   string
+		row    int
+	}{
+		{"v", "bom.csv", 3},
+		{"vmax", "db.csv", 5},
+		{"package", "db.csv", 5},
+		{"tmax", "bom.csv", 3},
+		{"", "bom.csv", 3},
+	}
+
+	for _, tt := range tests {
+		d := &Diagnostic{Name: c.Name, Column: tt.column}
+		locate(d, c, rows)
+		if d.File != tt.file || d.Row != tt.row {
+			t.Errorf("%s: %s:%d, want %s:%d", tt.column, d.File, d.Row, tt.file, tt.row)
+		}
+	}
+
+	d := &Diagnostic{Name: "R1", Column: "p"}
+	locate(d, &Component{Name: "R1"}, rows)
+	if d.File != "" || d.Row != 0 {
+		t.Errorf("component not in the files located at %s:%d", d.File, d.Row)
+	}
+}